@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/pflag"
+
+	"weblogic-operator/pkg/logexport"
+	"weblogic-operator/pkg/util/flags"
+	"weblogic-operator/pkg/util/logs"
+)
+
+func main() {
+	var dir = pflag.String("dir", "/u01/oracle/logs", "Directory holding a directory of logs per server.")
+	var domain = pflag.String("domain", "", "Name of the domain the records are labelled with.")
+	var interval = pflag.Duration("interval", time.Second, "How often the logs are checked for new records.")
+	var quietPeriod = pflag.Duration("quiet-period", 5*time.Second, "How long the logs must not grow once stopped before exiting.")
+	var gracePeriod = pflag.Duration("grace-period", 25*time.Second, "How long the logs are followed at most once stopped.")
+
+	flags.InitFlags()
+	logs.InitLogs()
+	defer logs.FlushLogs()
+
+	glog.V(2).Infof("Exporting the WebLogic logs in %s", *dir)
+
+	// Follow the logs of the stopping server, then print what was read
+	stopChan := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stopChan)
+	}()
+
+	logexport.NewExporter(*dir, *domain, *interval, *quietPeriod, *gracePeriod, os.Stdout).Run(stopChan)
+}
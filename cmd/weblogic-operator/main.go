@@ -9,16 +9,28 @@ import (
 	"weblogic-operator/pkg/operator"
 	"weblogic-operator/pkg/util/flags"
 	"weblogic-operator/pkg/util/logs"
+	"weblogic-operator/pkg/version"
+	"weblogic-operator/pkg/webhook"
 )
 
 func main() {
 	var kubeConfigFile = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information.")
+	var configPath = pflag.String("config", "", "Path to the operator configuration file, reloaded when it changes.")
+	var namespaceConfig operator.NamespaceConfig
+	pflag.StringSliceVar(&namespaceConfig.Namespaces, "namespaces", nil, "Comma separated namespaces to manage. Defaults to all namespaces.")
+	pflag.StringVar(&namespaceConfig.Selector, "namespace-selector", "", "Label selector of the namespaces to manage, watched for namespaces being labelled or removed.")
+	var webhookConfig webhook.Config
+	pflag.StringVar(&webhookConfig.Address, "scaling-webhook-address", ":9999", "Address the WLDF scaling webhook listens on.")
+	pflag.StringVar(&webhookConfig.CredentialsDir, "scaling-webhook-credentials", "", "Directory holding the username and password files of a basic-auth Secret. Enables the WLDF scaling webhook.")
+	pflag.StringVar(&webhookConfig.CertFile, "scaling-webhook-tls-cert", "", "Certificate file to serve the WLDF scaling webhook over HTTPS.")
+	pflag.StringVar(&webhookConfig.KeyFile, "scaling-webhook-tls-key", "", "Private key file to serve the WLDF scaling webhook over HTTPS.")
+	pflag.BoolVar(&webhookConfig.AllowHTTP, "scaling-webhook-allow-http", false, "Serve the WLDF scaling webhook over plain HTTP when no certificate is given.")
 
 	flags.InitFlags()
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
-	glog.V(2).Info("Starting Weblogic operator")
+	glog.V(2).Infof("Starting Weblogic operator version %s", version.Version)
 
 	cfg, err := clientcmd.BuildConfigFromFlags("", *kubeConfigFile)
 	if err != nil {
@@ -26,7 +38,7 @@ func main() {
 		panic(err.Error())
 	}
 
-	operator, err := operator.NewWeblogicOperator(cfg)
+	operator, err := operator.NewWeblogicOperator(cfg, *configPath, namespaceConfig, webhookConfig)
 	if err != nil {
 		glog.Errorf("Failed to initialize the operator: %s", err)
 		panic(err.Error())
@@ -0,0 +1,154 @@
+// Package config holds the settings of the operator, read from an optional
+// YAML file, typically mounted from a ConfigMap.
+package config
+
+import (
+	goflag "flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/version"
+)
+
+// Config are the settings of the operator. Storage and script locations are
+// structural and only read at start; the others are reloaded when the file
+// changes.
+type Config struct {
+	// LogLevel is the glog verbosity, overriding --v when set. Removing it
+	// restores --v.
+	// +optional
+	LogLevel *int `json:"logLevel,omitempty"`
+	// ResyncPeriod is how often the controllers resync their resources.
+	// Defaults to 30s.
+	// +optional
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+	// WebLogicImage is the image, without tag, of the server pods. The tag is
+	// the version of the domain. Defaults to docker.io/store/oracle/weblogic.
+	// +optional
+	WebLogicImage string `json:"weblogicImage,omitempty"`
+	// ImagePullSecret names the Secret the server pods pull their image with.
+	// Defaults to weblogic-docker-store.
+	// +optional
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+	// LogExporterImage is the image of the log exporter sidecar of domains
+	// exporting their logs. Defaults to the image of the operator, which
+	// ships the log exporter, at the version of the operator.
+	// +optional
+	LogExporterImage string `json:"logExporterImage,omitempty"`
+	// StorageClaimName is the PersistentVolumeClaim holding the domains.
+	// Defaults to weblogic-operator-claim.
+	// +optional
+	StorageClaimName string `json:"storageClaimName,omitempty"`
+	// ScriptsDir holds the scripts the operator delivers to the server pods.
+	// Defaults to /scripts.
+	// +optional
+	ScriptsDir string `json:"scriptsDir,omitempty"`
+}
+
+var (
+	mutex   sync.RWMutex
+	current = Default()
+	// startupLogLevel is the --v given on the command line, recorded before
+	// the first logLevel setting overrides it
+	startupLogLevel *string
+)
+
+// Default returns the settings used without a configuration file.
+func Default() *Config {
+	config := &Config{}
+	config.applyDefaults()
+	return config
+}
+
+func (c *Config) applyDefaults() {
+	if c.ResyncPeriod.Duration == 0 {
+		c.ResyncPeriod.Duration = 30 * time.Second
+	}
+	if c.WebLogicImage == "" {
+		c.WebLogicImage = constants.WeblogicImageName
+	}
+	if c.ImagePullSecret == "" {
+		c.ImagePullSecret = "weblogic-docker-store"
+	}
+	if c.LogExporterImage == "" {
+		c.LogExporterImage = version.Image + ":" + version.Version
+	}
+	if c.StorageClaimName == "" {
+		c.StorageClaimName = "weblogic-operator-claim"
+	}
+	if c.ScriptsDir == "" {
+		c.ScriptsDir = "/scripts"
+	}
+}
+
+// Validate returns an error if a setting is out of range.
+func (c *Config) Validate() error {
+	if c.LogLevel != nil && *c.LogLevel < 0 {
+		return fmt.Errorf("logLevel must not be negative")
+	}
+	if c.ResyncPeriod.Duration < 0 {
+		return fmt.Errorf("resyncPeriod must not be negative")
+	}
+	if !filepath.IsAbs(c.ScriptsDir) {
+		return fmt.Errorf("scriptsDir must be an absolute path, not %q", c.ScriptsDir)
+	}
+	return nil
+}
+
+// Load reads, defaults and validates the configuration file at path.
+func Load(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %s", path, err)
+	}
+	config.applyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %s", path, err)
+	}
+	return config, nil
+}
+
+// Current returns the settings in effect. Callers must not modify them.
+func Current() *Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set puts the given settings into effect, including the log level. Without
+// a log level the one given on the command line applies.
+func Set(config *Config) {
+	mutex.Lock()
+	current = config
+	if startupLogLevel == nil {
+		if flag := goflag.Lookup("v"); flag != nil {
+			level := flag.Value.String()
+			startupLogLevel = &level
+		}
+	}
+	restored := startupLogLevel
+	mutex.Unlock()
+
+	if config.LogLevel != nil {
+		goflag.Set("v", strconv.Itoa(*config.LogLevel))
+	} else if restored != nil {
+		goflag.Set("v", *restored)
+	}
+}
+
+// WebLogicImage returns the image of the server pods for the given version.
+func WebLogicImage(version string) string {
+	return fmt.Sprintf("%s:%s", Current().WebLogicImage, version)
+}
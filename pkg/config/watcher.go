@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// WatchInterval is how often the configuration file is checked for changes.
+var WatchInterval = 10 * time.Second
+
+// Watcher reloads the configuration file when it changes. ConfigMap volumes
+// are updated by swapping a symlink, so the content is compared rather than
+// relying on file events.
+type Watcher struct {
+	path    string
+	content []byte
+	// OnResyncPeriodChange is called with the new period when it changed.
+	OnResyncPeriodChange func(time.Duration)
+}
+
+// NewWatcher loads the configuration file at path, puts it into effect and
+// returns a Watcher for its changes.
+func NewWatcher(path string) (*Watcher, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	Set(config)
+	glog.Infof("Loaded configuration from %s", path)
+
+	return &Watcher{path: path, content: content}, nil
+}
+
+func (w *Watcher) reload() {
+	content, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		glog.Errorf("Unable to read configuration file %s: %s", w.path, err)
+		return
+	}
+	if bytes.Equal(content, w.content) {
+		return
+	}
+	w.content = content
+
+	config, err := Load(w.path)
+	if err != nil {
+		glog.Errorf("Keeping the current configuration: %s", err)
+		return
+	}
+
+	// Structural settings only apply after a restart
+	previous := Current()
+	if config.StorageClaimName != previous.StorageClaimName || config.ScriptsDir != previous.ScriptsDir {
+		glog.Warningf("Storage and script settings of %s changed, restart the operator to apply them", w.path)
+		config.StorageClaimName = previous.StorageClaimName
+		config.ScriptsDir = previous.ScriptsDir
+	}
+
+	Set(config)
+	glog.Infof("Reloaded configuration from %s", w.path)
+
+	if config.ResyncPeriod.Duration != previous.ResyncPeriod.Duration && w.OnResyncPeriodChange != nil {
+		w.OnResyncPeriodChange(config.ResyncPeriod.Duration)
+	}
+}
+
+// Run checks the configuration file for changes until stopChan is closed.
+func (w *Watcher) Run(stopChan <-chan struct{}) {
+	wait.Until(w.reload, WatchInterval, stopChan)
+}
@@ -14,11 +14,60 @@ const (
 	WebLogicDomainResourceKindPlural = "weblogicdomains"
 	WebLogicDomainSchemeVersion      = "v1"
 
+	//Label naming the WebLogic server a pod is running
+	WebLogicServerNameLabel = "WebLogicServerName.v1.weblogic.oracle.com"
+
 	//Constants for Horizontal Pod Autoscaling
-	HorizontalPodAutoscalerKind        = "ReplicaSet"
-	HorizontalPodAutoscalerKindPlural  = "replicasets"
-	HorizontalPodAutoscalerName        = "managedserver-scaler"
-	HorizontalPodAutoscalerTargetLabel = "managedserver"
+	HorizontalPodAutoscalerAPIVersion = "extensions/v1beta1"
+	HorizontalPodAutoscalerKind       = "ReplicaSet"
+	HorizontalPodAutoscalerKindPlural = "replicasets"
 
 	WeblogicImageName = "docker.io/store/oracle/weblogic"
+
+	//Constants for the declarative domain model
+	DomainModelConfigMapKey   = "domainModel.yaml"
+	DomainModelScriptKey      = "applyDomainModel.py"
+	DomainModelMountPath      = "/u01/oracle/domain-model"
+	DomainModelHashAnnotation = "weblogic.oracle.com/domain-model-hash"
+
+	//Constants for the scripts run in the server pods
+	ScriptsMountPath      = "/u01/oracle/scripts"
+	ScriptsHashAnnotation = "weblogic.oracle.com/scripts-hash"
+	ScriptsVersionLabel   = "weblogic.oracle.com/scripts-version"
+
+	//Constants for exporting the server logs through a sidecar
+	LogExportMountPath      = "/u01/oracle/logs"
+	LogExporterCommand      = "/weblogic-log-exporter"
+	LogExporterContainer    = "log-exporter"
+	LogExportHashAnnotation = "weblogic.oracle.com/log-export-hash"
+	LogExportDrainSeconds   = 5
+
+	//Constants for configuration overrides
+	ConfigOverridesMountPath      = "/u01/oracle/config-overrides"
+	ConfigOverridesHashAnnotation = "weblogic.oracle.com/config-overrides-hash"
+
+	//Constants for the additions users make to the server pods
+	ServerPodHashAnnotation  = "weblogic.oracle.com/server-pod-hash"
+	OperatorAnnotationPrefix = "weblogic.oracle.com/"
+
+	//Annotation recording a digest of the whole pod template of a replica set
+	PodTemplateHashAnnotation = "weblogic.oracle.com/pod-template-hash"
+
+	//Annotation marking the pods of servers shut down for a scale down, which
+	//health checks and restarts leave alone
+	ScaleDownAnnotation = "weblogic.oracle.com/scale-down"
+
+	//Constants for the credentials of the domain administrator, see adminCredentials.sh
+	AdminCredentialsMountPath = "/u01/oracle/admin-credentials"
+	AdminUsernameKey          = "username"
+	AdminPasswordKey          = "password"
+	DefaultAdminUsername      = "weblogic"
+
+	//Constants for TLS
+	TLSMountPath           = "/u01/oracle/tls"
+	TLSKeystorePasswordKey = "keystore.password"
+	TLSCACertificateKey    = "ca.crt"
+	TLSCAKeyKey            = "ca.key"
+	TLSCertificateKey      = "tls.crt"
+	TLSPrivateKeyKey       = "tls.key"
 )
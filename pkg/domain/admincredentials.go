@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/secrets"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateAdminCredentialsSecretForWebLogicDomain generates the credentials of
+// the domain administrator with a random password, unless the domain brings
+// its own Secret, which must hold a username and a password. Existing
+// credentials are kept, as the domain was created with them.
+func CreateAdminCredentialsSecretForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	client := clientset.CoreV1().Secrets(domain.Namespace)
+	existing, err := client.Get(domain.AdminSecretName(), metav1.GetOptions{})
+	if domain.Spec.AdminSecret != "" {
+		if err != nil {
+			glog.Errorf("Unable to get admin secret %s for %s: %s", domain.Spec.AdminSecret, domain.Name, err)
+			return err
+		}
+		if len(existing.Data[constants.AdminUsernameKey]) == 0 || len(existing.Data[constants.AdminPasswordKey]) == 0 {
+			return fmt.Errorf("admin secret %s of domain %s needs a %s and a %s",
+				existing.Name, domain.Name, constants.AdminUsernameKey, constants.AdminPasswordKey)
+		}
+		return nil
+	}
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		glog.Errorf("Unable to get admin secret for %s: %s", domain.Name, err)
+		return err
+	}
+
+	password := make([]byte, 16)
+	if _, err := rand.Read(password); err != nil {
+		return err
+	}
+	glog.V(4).Infof("Creating admin secret for domain %s", domain.Name)
+	_, err = client.Create(secrets.NewAdminCredentialsSecret(domain, constants.DefaultAdminUsername, hex.EncodeToString(password)))
+	return err
+}
+
+// DeleteAdminCredentialsSecretForWebLogicDomain deletes the generated admin
+// Secret of the domain, if any.
+func DeleteAdminCredentialsSecretForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	if domain.Spec.AdminSecret != "" {
+		return nil
+	}
+
+	err := clientset.CoreV1().Secrets(domain.Namespace).Delete(domain.AdminSecretName(), nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete admin secret: %s", err)
+		return err
+	}
+	return nil
+}
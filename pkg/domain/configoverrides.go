@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/resources/replicasets"
+	"weblogic-operator/pkg/server"
+	"weblogic-operator/pkg/types"
+)
+
+// GetConfigOverridesForWebLogicDomain returns the ConfigMap of overrides
+// referenced by the domain, or nil if the domain has none.
+func GetConfigOverridesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) (*v1.ConfigMap, error) {
+	if domain.Spec.ConfigOverrides == "" {
+		return nil, nil
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps(domain.Namespace).Get(domain.Spec.ConfigOverrides, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("Unable to get config overrides %s for %s: %s", domain.Spec.ConfigOverrides, domain.Name, err)
+		return nil, err
+	}
+	return configMap, nil
+}
+
+// applyConfigOverridesToDomainModel merges the YAML files of the overrides
+// ConfigMap into the domain model. Resources are matched by name, so an
+// override only needs to restate the fields that differ per environment.
+func applyConfigOverridesToDomainModel(model *types.WebLogicDomainModel, overrides *v1.ConfigMap) (*types.WebLogicDomainModel, error) {
+	if overrides == nil {
+		return model, nil
+	}
+
+	// Apply the files in name order so the resulting model is stable.
+	keys := make([]string, 0, len(overrides.Data))
+	for key := range overrides.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		ext := filepath.Ext(key)
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		content := overrides.Data[key]
+
+		if model == nil {
+			model = &types.WebLogicDomainModel{}
+		}
+
+		overlay := &types.WebLogicDomainModel{}
+		if err := yaml.Unmarshal([]byte(content), overlay); err != nil {
+			return nil, fmt.Errorf("unable to parse %s of config overrides %s: %s", key, overrides.Name, err)
+		}
+		mergeDomainModel(model, overlay)
+	}
+	return model, nil
+}
+
+// copyDomainModel returns a copy of the model that can be merged into without
+// modifying the domain spec it came from.
+func copyDomainModel(model *types.WebLogicDomainModel) *types.WebLogicDomainModel {
+	return &types.WebLogicDomainModel{
+		DataSources:    append([]types.DataSource(nil), model.DataSources...),
+		JMSServers:     append([]types.JMSServer(nil), model.JMSServers...),
+		JMSModules:     append([]types.JMSModule(nil), model.JMSModules...),
+		WorkManagers:   append([]types.WorkManager(nil), model.WorkManagers...),
+		AppDeployments: append([]types.AppDeployment(nil), model.AppDeployments...),
+	}
+}
+
+func mergeDomainModel(model *types.WebLogicDomainModel, overlay *types.WebLogicDomainModel) {
+	for _, ds := range overlay.DataSources {
+		merged := false
+		for i := range model.DataSources {
+			if model.DataSources[i].Name == ds.Name {
+				mergeDataSource(&model.DataSources[i], ds)
+				merged = true
+			}
+		}
+		if !merged {
+			model.DataSources = append(model.DataSources, ds)
+		}
+	}
+
+	for _, jmsServer := range overlay.JMSServers {
+		model.JMSServers = replaceJMSServer(model.JMSServers, jmsServer)
+	}
+	for _, module := range overlay.JMSModules {
+		model.JMSModules = replaceJMSModule(model.JMSModules, module)
+	}
+	for _, wm := range overlay.WorkManagers {
+		model.WorkManagers = replaceWorkManager(model.WorkManagers, wm)
+	}
+	for _, app := range overlay.AppDeployments {
+		model.AppDeployments = replaceAppDeployment(model.AppDeployments, app)
+	}
+}
+
+// mergeDataSource copies the fields set in the override onto the data source,
+// which is what per-environment URLs and credentials need.
+func mergeDataSource(ds *types.DataSource, override types.DataSource) {
+	if override.JNDIName != "" {
+		ds.JNDIName = override.JNDIName
+	}
+	if override.URL != "" {
+		ds.URL = override.URL
+	}
+	if override.DriverName != "" {
+		ds.DriverName = override.DriverName
+	}
+	if override.User != "" {
+		ds.User = override.User
+	}
+	if override.PasswordSecret != nil {
+		ds.PasswordSecret = override.PasswordSecret
+	}
+	if override.InitialCapacity != 0 {
+		ds.InitialCapacity = override.InitialCapacity
+	}
+	if override.MaxCapacity != 0 {
+		ds.MaxCapacity = override.MaxCapacity
+	}
+	if override.TestTableName != "" {
+		ds.TestTableName = override.TestTableName
+	}
+	if len(override.Targets) > 0 {
+		ds.Targets = override.Targets
+	}
+}
+
+func replaceJMSServer(items []types.JMSServer, item types.JMSServer) []types.JMSServer {
+	for i := range items {
+		if items[i].Name == item.Name {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func replaceJMSModule(items []types.JMSModule, item types.JMSModule) []types.JMSModule {
+	for i := range items {
+		if items[i].Name == item.Name {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func replaceWorkManager(items []types.WorkManager, item types.WorkManager) []types.WorkManager {
+	for i := range items {
+		if items[i].Name == item.Name {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func replaceAppDeployment(items []types.AppDeployment, item types.AppDeployment) []types.AppDeployment {
+	for i := range items {
+		if items[i].Name == item.Name {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+// RollManagedServersForWebLogicDomain rebuilds the managed server ReplicaSets
+// of a domain whose pod template changed with the domain, e.g. its config
+// overrides, scripts or image, which restarts their pods one at a time.
+func RollManagedServersForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=managedserver", domain.Name)}
+	replicaSets, err := clientset.ExtensionsV1beta1().ReplicaSets(domain.Namespace).List(opts)
+	if err != nil {
+		glog.Errorf("Unable to list managed server replica sets for %s: %s", domain.Name, err)
+		return err
+	}
+
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		// A replica set outliving its server set is left to its deletion
+		managedServer, err := server.GetServerForReplicaSet(rs, types.ServerRESTClient)
+		if err != nil {
+			glog.Errorf("Failed to find server for replica set %s: %s", rs.Name, err)
+			continue
+		}
+		managedServer.Spec.Domain = *domain
+
+		service, err := server.GetServiceForWebLogicManagedServer(managedServer, clientset)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			return fmt.Errorf("no service found for server %s", managedServer.Name)
+		}
+
+		if !replicasets.TemplateChanged(rs, replicasets.NewForServer(managedServer, service.Name)) {
+			continue
+		}
+
+		glog.V(2).Infof("Pod template of domain %s changed, restarting servers of %s", domain.Name, managedServer.Name)
+		_, err = server.UpdateReplicaSetForWebLogicManagedServer(clientset, managedServer, service)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package domain
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -24,6 +27,10 @@ type StoreToWebLogicDomainReplicaSetLister struct {
 	cache.Store
 }
 
+type StoreToWebLogicDomainConfigMapLister struct {
+	cache.Store
+}
+
 // The WebLogicDomainController watches the Kubernetes API for changes to WebLogicDomain resources
 type WebLogicDomainController struct {
 	client                        kubernetes.Interface
@@ -34,6 +41,8 @@ type WebLogicDomainController struct {
 	weblogicDomainStore           StoreToWebLogicDomainLister
 	weblogicDomainReplicaSet      cache.Controller
 	weblogicDomainReplicaSetStore StoreToWebLogicDomainReplicaSetLister
+	weblogicDomainConfigMap       cache.Controller
+	weblogicDomainConfigMapStore  StoreToWebLogicDomainConfigMapLister
 }
 
 // NewController creates a new WebLogicDomainController.
@@ -80,6 +89,25 @@ func NewController(kubeClient kubernetes.Interface, restClient *rest.RESTClient,
 		replicaSetHandler,
 	)
 
+	configMapHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    m.onConfigMapAdd,
+		UpdateFunc: m.onConfigMapUpdate,
+	}
+
+	m.weblogicDomainConfigMapStore.Store, m.weblogicDomainConfigMap = cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return kubeClient.CoreV1().ConfigMaps(namespace).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return kubeClient.CoreV1().ConfigMaps(namespace).Watch(options)
+			},
+		},
+		&v1.ConfigMap{},
+		resyncPeriod,
+		configMapHandler,
+	)
+
 	return &m, nil
 }
 
@@ -110,6 +138,10 @@ func (m *WebLogicDomainController) onUpdate(old, cur interface{}) {
 	if curDomain.ResourceVersion == oldDomain.ResourceVersion {
 		return
 	}
+	if statusOnlyUpdate(oldDomain, curDomain) {
+		// The operator recorded the status, nothing to reconcile
+		return
+	}
 
 	err := createWebLogicDomain(curDomain, m.client, m.restClient)
 	if err != nil {
@@ -117,6 +149,14 @@ func (m *WebLogicDomainController) onUpdate(old, cur interface{}) {
 	}
 }
 
+// statusOnlyUpdate returns true if the update of a domain left everything but
+// its status unchanged.
+func statusOnlyUpdate(old, cur *types.WebLogicDomain) bool {
+	return reflect.DeepEqual(old.Spec, cur.Spec) &&
+		reflect.DeepEqual(old.Labels, cur.Labels) &&
+		reflect.DeepEqual(old.Annotations, cur.Annotations)
+}
+
 func (m *WebLogicDomainController) onReplicaSetAdd(obj interface{}) {
 	glog.V(4).Info("WebLogicDomainController.onReplicaSetAdd() called")
 
@@ -146,12 +186,53 @@ func (m *WebLogicDomainController) onReplicaSetUpdate(old, new interface{}) {
 	m.onReplicaSetAdd(new)
 }
 
+// onConfigMapAdd reconciles every domain that consumes the given ConfigMap.
+func (m *WebLogicDomainController) onConfigMapAdd(obj interface{}) {
+	configMap := obj.(*v1.ConfigMap)
+
+	for _, item := range m.weblogicDomainStore.List() {
+		weblogicDomain := item.(*types.WebLogicDomain)
+		if !referencesConfigMap(weblogicDomain, configMap) {
+			continue
+		}
+
+		glog.V(4).Infof("Config map %s of domain %s changed", configMap.Name, weblogicDomain.Name)
+		err := createWebLogicDomain(weblogicDomain, m.client, m.restClient)
+		if err != nil {
+			glog.Errorf("Failed to update domain: %s", err)
+		}
+	}
+}
+
+func (m *WebLogicDomainController) onConfigMapUpdate(old, cur interface{}) {
+	curConfigMap := cur.(*v1.ConfigMap)
+	oldConfigMap := old.(*v1.ConfigMap)
+	if curConfigMap.ResourceVersion == oldConfigMap.ResourceVersion {
+		return
+	}
+	m.onConfigMapAdd(cur)
+}
+
+// syncServerHealth records the server states of all domains.
+func (m *WebLogicDomainController) syncServerHealth() {
+	now := time.Now()
+	for _, obj := range m.weblogicDomainStore.List() {
+		weblogicDomain := obj.(*types.WebLogicDomain)
+		err := SyncServerHealthForWebLogicDomain(m.client, m.restClient, weblogicDomain, now)
+		if err != nil {
+			glog.Errorf("Failed to sync server health of domain %s: %s", weblogicDomain.Name, err)
+		}
+	}
+}
+
 // Run the WebLogic controller
 func (m *WebLogicDomainController) Run(stopChan <-chan struct{}) {
 	glog.Infof("Starting WebLogic Domain controller")
 	go m.weblogicDomainController.Run(stopChan)
 	//go m.weblogicStatefulSetController.Run(stopChan)
 	go m.weblogicDomainReplicaSet.Run(stopChan)
+	go m.weblogicDomainConfigMap.Run(stopChan)
+	go wait.Until(m.syncServerHealth, HealthCheckInterval, stopChan)
 	<-stopChan
 	glog.Infof("Shutting down WebLogic Domain controller")
 }
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
@@ -14,9 +16,9 @@ import (
 	"weblogic-operator/pkg/constants"
 	"weblogic-operator/pkg/resources/replicasets"
 	"weblogic-operator/pkg/resources/services"
+	"weblogic-operator/pkg/server"
 	"weblogic-operator/pkg/types"
-	"io/ioutil"
-	"encoding/json"
+	"weblogic-operator/pkg/util/hash"
 )
 
 // HasDomainNameLabel returns true if the given labels map matches the given
@@ -54,8 +56,10 @@ func GetReplicaSetForWebLogicDomain(domain *types.WebLogicDomain, kubeClient kub
 	return nil, nil
 }
 
-// CreateReplicaSetForWebLogicDomain will create a new Kubernetes ReplicaSet based on a predefined template
-func CreateReplicaSetForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain, service *v1.Service) (controller *v1beta1.ReplicaSet, err error) {
+// CreateReplicaSetForWebLogicDomain will create a new Kubernetes ReplicaSet based on a predefined template.
+// If the ReplicaSet exists but was built from a different pod template it is
+// updated and the admin server restarted.
+func CreateReplicaSetForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain, service *v1.Service, model *types.WebLogicDomainModel) (controller *v1beta1.ReplicaSet, err error) {
 	// Find ReplicaSet and if it does not exist create it
 	existingReplicaSet, err := GetReplicaSetForWebLogicDomain(domain, clientset)
 	if err != nil {
@@ -63,13 +67,31 @@ func CreateReplicaSetForWebLogicDomain(clientset kubernetes.Interface, domain *t
 		return nil, err
 	}
 
+	rs := replicasets.NewForDomain(domain, service.Name, model)
+
 	if existingReplicaSet != nil {
 		glog.V(2).Infof("Replica set with label %s already exists", getLabelSelectorForDomain(domain))
-		return existingReplicaSet, nil
+		if !replicasets.TemplateChanged(existingReplicaSet, rs) {
+			if existingReplicaSet.Spec.Replicas != nil && *existingReplicaSet.Spec.Replicas == *rs.Spec.Replicas {
+				return existingReplicaSet, nil
+			}
+
+			// Started or stopped by the serverStartPolicy, no restart needed
+			glog.V(2).Infof("Scaling replica set %s to %d", existingReplicaSet.Name, *rs.Spec.Replicas)
+			scaled := *existingReplicaSet
+			scaled.Spec.Replicas = rs.Spec.Replicas
+			return clientset.ExtensionsV1beta1().ReplicaSets(domain.Namespace).Update(&scaled)
+		}
+
+		glog.V(2).Infof("Configuration of %s changed, updating replica set %s", domain.Name, existingReplicaSet.Name)
+		updated, err := clientset.ExtensionsV1beta1().ReplicaSets(domain.Namespace).Update(rs)
+		if err != nil {
+			return nil, err
+		}
+		return updated, RestartAdminServerPodsForWebLogicDomain(clientset, domain)
 	}
 
 	glog.V(4).Infof("Creating a new replica set for domain %s", domain.Name)
-	rs := replicasets.NewForDomain(domain, service.Name)
 
 	glog.V(4).Infof("Creating domain %+v", rs)
 	return clientset.ExtensionsV1beta1().ReplicaSets(domain.Namespace).Create(rs)
@@ -103,12 +125,133 @@ func createWebLogicDomain(domain *types.WebLogicDomain, kubeClient kubernetes.In
 		return updateWebLogicDomain(domain, restClient)
 	}
 
+	err := domain.ValidatePorts()
+	if err != nil {
+		glog.Errorf("Invalid ports for domain %s: %s", domain.Name, err)
+		return err
+	}
+
+	err = domain.ValidateTLS()
+	if err != nil {
+		glog.Errorf("Invalid TLS settings for domain %s: %s", domain.Name, err)
+		return err
+	}
+
+	err = domain.ValidateNodeManager()
+	if err != nil {
+		glog.Errorf("Invalid node manager settings for domain %s: %s", domain.Name, err)
+		return err
+	}
+
+	err = domain.ValidateNetworkPolicy()
+	if err != nil {
+		glog.Errorf("Invalid network policy for domain %s: %s", domain.Name, err)
+		return err
+	}
+
+	err = domain.ValidateServerStartPolicy()
+	if err != nil {
+		glog.Errorf("Invalid server start policy for domain %s: %s", domain.Name, err)
+		return err
+	}
+
+	err = domain.Spec.Shutdown.Validate()
+	if err != nil {
+		glog.Errorf("Invalid shutdown options for domain %s: %s", domain.Name, err)
+		return err
+	}
+
+	overrides, err := GetConfigOverridesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	// Record the overrides the servers should run with and reconcile with
+	// them right away, as a status update is not reconciled again.
+	overridesHash := ""
+	if overrides != nil {
+		overridesHash = hash.ForMap(overrides.Data)
+	}
+	if domain.Status.ConfigOverridesHash != overridesHash {
+		glog.V(2).Infof("Config overrides of domain %s changed", domain.Name)
+		// Do not modify the cached domain
+		updated := *domain
+		updated.Status.ConfigOverridesHash = overridesHash
+		err = updateWebLogicDomain(&updated, restClient)
+		if err != nil {
+			return err
+		}
+		domain = &updated
+	}
+
 	domainService, err := CreateServiceForWebLogicDomain(kubeClient, domain)
 	if err != nil {
 		return err
 	}
 
-	_, err = CreateReplicaSetForWebLogicDomain(kubeClient, domain, domainService)
+	err = CreateOrUpdateServerServicesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = CreateOrUpdateIngressesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = CreateOrUpdateNetworkPoliciesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = CreateOrUpdateTLSSecretForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = CreateAdminCredentialsSecretForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = CreateConfigMapForScripts(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	model, err := GetDomainModelForWebLogicDomain(kubeClient, domain, overrides)
+	if err != nil {
+		return err
+	}
+
+	if model != nil {
+		err = CreateOrUpdateConfigMapForDomainModel(kubeClient, domain, model)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = CreateReplicaSetForWebLogicDomain(kubeClient, domain, domainService, model)
+	if err != nil {
+		return err
+	}
+
+	err = CreateOrUpdatePodDisruptionBudgetForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = RollManagedServersForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = ApplyServerStartPolicyForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteUnusedConfigMapsForScripts(kubeClient, domain)
 	if err != nil {
 		return err
 	}
@@ -139,6 +282,46 @@ func deleteWebLogicDomain(domain *types.WebLogicDomain, kubeClient kubernetes.In
 		return err
 	}
 
+	err = DeleteConfigMapForDomainModel(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteConfigMapForScripts(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeletePodDisruptionBudgetForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteServerServicesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteIngressesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteNetworkPoliciesForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteTLSSecretForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
+	err = DeleteAdminCredentialsSecretForWebLogicDomain(kubeClient, domain)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -205,35 +388,43 @@ func GetDomainForReplicaSet(replicaset *v1beta1.ReplicaSet, restClient *rest.RES
 }
 
 func updateDomainWithReplicaSet(domain *types.WebLogicDomain, replicaSet *v1beta1.ReplicaSet, kubeClient kubernetes.Interface, restClient *rest.RESTClient) (err error) {
-	err = PopulateServerDetailsForWebLogicDomain(domain, restClient)
+	err = server.LabelServerPodsForWebLogicDomain(kubeClient, domain.Namespace, domain.Name)
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func PopulateServerDetailsForWebLogicDomain(domain *types.WebLogicDomain, restClient *rest.RESTClient) error {
-	serverListFile := "/u01/oracle/user_projects/domains/" + domain.Name + "/serverList.json"
-
-	file, err := ioutil.ReadFile(serverListFile)
+	err = PopulateServerDetailsForWebLogicDomain(kubeClient, domain, restClient)
 	if err != nil {
-		glog.V(4).Infof(err.Error())
+		return err
 	}
+	return DeleteUnusedConfigMapsForScripts(kubeClient, domain)
+}
 
-	err = json.Unmarshal(file, &domain.Spec.ServersAvailable)
+// PopulateServerDetailsForWebLogicDomain records the servers run by the pods
+// of a domain, as labelled, in its serversAvailable.
+func PopulateServerDetailsForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain, restClient *rest.RESTClient) error {
+	pods, err := serverPodsForWebLogicDomain(clientset, domain)
 	if err != nil {
-		glog.V(4).Infof(err.Error())
+		glog.Errorf("Unable to list server pods for %s: %s", domain.Name, err)
+		return err
 	}
 
-	//err = json.Unmarshal(file, &domain.Spec.ServersRunning)
-	//if err != nil {
-	//	glog.V(4).Infof(err.Error())
-	//}
+	names := make([]string, 0, len(pods))
+	for name := range pods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	err = updateWebLogicDomain(domain, restClient)
-	if err != nil {
-		return err
+	var servers []types.Server
+	for _, name := range names {
+		pod := pods[name]
+		servers = append(servers, types.Server{Host: pod.Status.PodIP, ServerName: name, PodName: pod.Name})
+	}
+	if reflect.DeepEqual(servers, domain.Spec.ServersAvailable) {
+		return nil
 	}
 
-	return nil
+	// Do not modify the cached domain
+	updated := *domain
+	updated.Spec.ServersAvailable = servers
+	return updateWebLogicDomain(&updated, restClient)
 }
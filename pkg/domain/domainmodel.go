@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/configmaps"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/wlst"
+)
+
+// GetDomainModelForWebLogicDomain returns the domain model declared inline in
+// the domain spec or in the ConfigMap it references, with the YAML files of
+// the config overrides merged in. A domain whose model was removed gets an
+// empty one, so the resources created for the old model are deleted. It
+// returns nil if the domain never had a model.
+func GetDomainModelForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain, overrides *v1.ConfigMap) (*types.WebLogicDomainModel, error) {
+	if domain.Spec.DomainModelConfigMap == "" {
+		if domain.Spec.DomainModel == nil {
+			model, err := applyConfigOverridesToDomainModel(nil, overrides)
+			if err != nil || model != nil {
+				return model, err
+			}
+			return removedDomainModel(clientset, domain)
+		}
+		return applyConfigOverridesToDomainModel(copyDomainModel(domain.Spec.DomainModel), overrides)
+	}
+
+	if domain.Spec.DomainModel != nil {
+		return nil, fmt.Errorf("domain %s sets both domainModel and domainModelConfigMap", domain.Name)
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps(domain.Namespace).Get(domain.Spec.DomainModelConfigMap, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("Unable to get domain model config map %s for %s: %s", domain.Spec.DomainModelConfigMap, domain.Name, err)
+		return nil, err
+	}
+
+	content, ok := configMap.Data[constants.DomainModelConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("config map %s has no %s key", configMap.Name, constants.DomainModelConfigMapKey)
+	}
+
+	model := &types.WebLogicDomainModel{}
+	if err := yaml.Unmarshal([]byte(content), model); err != nil {
+		return nil, fmt.Errorf("unable to parse domain model from config map %s: %s", configMap.Name, err)
+	}
+	return applyConfigOverridesToDomainModel(model, overrides)
+}
+
+// CreateOrUpdateConfigMapForDomainModel renders the WLST script for the domain
+// model into the ConfigMap mounted by the admin server pod.
+func CreateOrUpdateConfigMapForDomainModel(clientset kubernetes.Interface, domain *types.WebLogicDomain, model *types.WebLogicDomainModel) error {
+	configMap := configmaps.NewDomainModelConfigMap(domain, wlst.NewDomainModelScript(domain, model))
+
+	existing, err := clientset.CoreV1().ConfigMaps(domain.Namespace).Get(configMap.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		glog.V(4).Infof("Creating domain model config map for domain %s", domain.Name)
+		_, err = clientset.CoreV1().ConfigMaps(domain.Namespace).Create(configMap)
+		return err
+	}
+	if err != nil {
+		glog.Errorf("Unable to get domain model config map for %s: %s", domain.Name, err)
+		return err
+	}
+
+	existing.Data = configMap.Data
+	glog.V(4).Infof("Updating domain model config map for domain %s", domain.Name)
+	_, err = clientset.CoreV1().ConfigMaps(domain.Namespace).Update(existing)
+	return err
+}
+
+// removedDomainModel returns an empty model if a model was applied to the
+// domain before, or else nil.
+func removedDomainModel(clientset kubernetes.Interface, domain *types.WebLogicDomain) (*types.WebLogicDomainModel, error) {
+	_, err := clientset.CoreV1().ConfigMaps(domain.Namespace).Get(configmaps.DomainModelConfigMapName(domain), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		glog.Errorf("Unable to get domain model config map for %s: %s", domain.Name, err)
+		return nil, err
+	}
+	return &types.WebLogicDomainModel{}, nil
+}
+
+// DeleteConfigMapForDomainModel deletes the generated domain model ConfigMap, if any.
+func DeleteConfigMapForDomainModel(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	err := clientset.CoreV1().ConfigMaps(domain.Namespace).Delete(configmaps.DomainModelConfigMapName(domain), nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete domain model config map: %s", err)
+		return err
+	}
+	return nil
+}
+
+// RestartAdminServerPodsForWebLogicDomain deletes the admin server pods of a
+// domain so its ReplicaSet recreates them from the current template.
+func RestartAdminServerPodsForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s,%s=adminserver", getLabelSelectorForDomain(domain), domain.Name)}
+	pods, err := clientset.CoreV1().Pods(domain.Namespace).List(opts)
+	if err != nil {
+		glog.Errorf("Unable to list admin server pods for %s: %s", domain.Name, err)
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		glog.V(2).Infof("Restarting admin server pod %s of domain %s", pod.Name, domain.Name)
+		err = clientset.CoreV1().Pods(domain.Namespace).Delete(pod.Name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// referencesConfigMap returns true if the domain consumes the given ConfigMap.
+func referencesConfigMap(domain *types.WebLogicDomain, configMap *v1.ConfigMap) bool {
+	if domain.Namespace != configMap.Namespace {
+		return false
+	}
+	return domain.Spec.DomainModelConfigMap == configMap.Name || domain.Spec.ConfigOverrides == configMap.Name
+}
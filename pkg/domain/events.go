@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/events"
+)
+
+// RecordEventForWebLogicDomain records an Event on the given WebLogicDomain.
+func RecordEventForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain, eventType, reason, message string) {
+	events.Record(clientset, v1.ObjectReference{
+		APIVersion:      constants.WebLogicGroupName + "/" + constants.WebLogicDomainSchemeVersion,
+		Kind:            constants.WebLogicDomainResourceKind,
+		Namespace:       domain.Namespace,
+		Name:            domain.Name,
+		UID:             domain.UID,
+		ResourceVersion: domain.ResourceVersion,
+	}, eventType, reason, message)
+}
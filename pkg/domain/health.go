@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/services"
+	"weblogic-operator/pkg/server"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/restart"
+	"weblogic-operator/pkg/wlsrest"
+)
+
+// HealthCheckInterval is how often the states of the servers of a domain are synced.
+const HealthCheckInterval = 30 * time.Second
+
+// serverPodsForWebLogicDomain returns the pods of a domain by the name of the
+// WebLogic server they run. Pods not yet running a server are left out.
+func serverPodsForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) (map[string]v1.Pod, error) {
+	serverPods := map[string]v1.Pod{}
+
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=adminserver", domain.Name)}
+	pods, err := clientset.CoreV1().Pods(domain.Namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			serverPods[services.AdminServerName] = pod
+		}
+	}
+
+	opts = metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=managedserver", domain.Name)}
+	pods, err = clientset.CoreV1().Pods(domain.Namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		serverName := pod.Labels[constants.WebLogicServerNameLabel]
+		if serverName != "" && pod.DeletionTimestamp == nil {
+			serverPods[serverName] = pod
+		}
+	}
+	return serverPods, nil
+}
+
+func sameServerStatuses(a, b []types.ServerStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		// Since only changes along with the state
+		if a[i].Name != b[i].Name || a[i].Pod != b[i].Pod || a[i].State != b[i].State ||
+			a[i].Health != b[i].Health || a[i].Stuck != b[i].Stuck {
+			return false
+		}
+	}
+	return true
+}
+
+// SyncServerHealthForWebLogicDomain records the states of the servers of a
+// domain, as reported by its admin server, in the domain's status. Servers
+// stuck in ADMIN, FAILED or UNKNOWN past the grace period are reported with a
+// Warning event and, if the domain asks for it, their pods are restarted.
+// Failed managed servers are restarted following the restart policy of their
+// server set instead, when it has one. Servers shut down for a scale down are
+// reported but never restarted.
+func SyncServerHealthForWebLogicDomain(clientset kubernetes.Interface, restClient *rest.RESTClient, domain *types.WebLogicDomain, now time.Time) error {
+	pods, err := serverPodsForWebLogicDomain(clientset, domain)
+	if err != nil {
+		glog.Errorf("Unable to list server pods for %s: %s", domain.Name, err)
+		return err
+	}
+	adminPod, found := pods[services.AdminServerName]
+	if !found || !restart.IsPodReady(&adminPod) {
+		if domain.AdminServerReplicas() == 0 && len(domain.Status.Servers) > 0 {
+			// The servers of a stopped domain are gone
+			updated := *domain
+			updated.Status.Servers = nil
+			return updateWebLogicDomain(&updated, restClient)
+		}
+		// Nothing to ask until the admin server is up
+		return nil
+	}
+
+	client, err := wlsrest.NewClientForDomain(clientset, domain)
+	if err != nil {
+		return err
+	}
+	states, err := client.ServerStates()
+	if err != nil {
+		glog.Errorf("Unable to read server states of %s: %s", domain.Name, err)
+		return err
+	}
+
+	previous := map[string]types.ServerStatus{}
+	for _, status := range domain.Status.Servers {
+		previous[status.Name] = status
+	}
+
+	names := make([]string, 0, len(pods))
+	for name := range pods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var statuses []types.ServerStatus
+	for _, name := range names {
+		pod := pods[name]
+		state := states[name]
+		status := types.ServerStatus{
+			Name:   name,
+			Pod:    pod.Name,
+			State:  state.State,
+			Health: state.Health,
+			Since:  metav1.NewTime(now),
+		}
+		if status.State == "" {
+			status.State = "UNKNOWN"
+		}
+
+		last, known := previous[name]
+		if known && last.Pod == status.Pod && last.State == status.State {
+			status.Since = last.Since
+		}
+		status.Stuck = types.IsStuckServerState(status.State) && now.Sub(status.Since.Time) >= domain.Spec.HealthCheck.StuckServerGracePeriod()
+
+		// Failed managed servers are restarted by the restart policy of their
+		// set, if any, rather than after the grace period
+		handled := false
+		setName := pod.Labels[constants.WebLogicManagedServerLabel]
+		if restart.IsPodScalingDown(&pod) {
+			// The replica set removes the pod once its server is down
+			handled = true
+		} else if setName != "" && types.IsFailedServerState(status.State) {
+			handled, err = server.RestartFailedServerForWebLogicManagedServer(clientset, types.ServerRESTClient, domain.Namespace, setName, name, &pod, now)
+			if err != nil {
+				glog.Errorf("Could not apply the restart policy of %s to %s: %s", setName, name, err)
+			}
+		}
+
+		if handled {
+			// Reported by the restart policy of the set or scaling down
+		} else if status.Stuck && domain.Spec.HealthCheck.RestartsStuckServers() {
+			message := fmt.Sprintf("Restarting pod %s of server %s stuck in %s since %s", pod.Name, name, status.State, status.Since.Format(time.RFC3339))
+			glog.V(2).Info(message)
+			RecordEventForWebLogicDomain(clientset, domain, v1.EventTypeWarning, "ServerRestarted", message)
+			err := clientset.CoreV1().Pods(pod.Namespace).Delete(pod.Name, nil)
+			if err != nil && !errors.IsNotFound(err) {
+				glog.Errorf("Could not restart pod %s: %s", pod.Name, err)
+			}
+		} else if status.Stuck && !last.Stuck {
+			RecordEventForWebLogicDomain(clientset, domain, v1.EventTypeWarning, "ServerStuck",
+				fmt.Sprintf("Server %s is stuck in %s since %s", name, status.State, status.Since.Format(time.RFC3339)))
+		}
+		statuses = append(statuses, status)
+	}
+
+	if sameServerStatuses(statuses, domain.Status.Servers) {
+		return nil
+	}
+
+	// Do not modify the cached domain
+	updated := *domain
+	updated.Status.Servers = statuses
+	return updateWebLogicDomain(&updated, restClient)
+}
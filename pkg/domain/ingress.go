@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"reflect"
+
+	"github.com/golang/glog"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/resources/ingresses"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateOrUpdateIngressesForWebLogicDomain renders the Ingresses declared by
+// the domain and deletes the ones it no longer declares.
+func CreateOrUpdateIngressesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	err := applyIngress(clientset, domain.Namespace, ingresses.Name(domain), ingresses.NewForDomain(domain))
+	if err != nil {
+		return err
+	}
+	return applyIngress(clientset, domain.Namespace, ingresses.AdminConsoleName(domain), ingresses.NewAdminConsoleForDomain(domain))
+}
+
+// DeleteIngressesForWebLogicDomain deletes the Ingresses of the domain.
+func DeleteIngressesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	err := deleteIngress(clientset, domain.Namespace, ingresses.Name(domain))
+	if err != nil {
+		return err
+	}
+	return deleteIngress(clientset, domain.Namespace, ingresses.AdminConsoleName(domain))
+}
+
+// applyIngress creates or updates the desired Ingress, or deletes the named
+// one if none is desired.
+func applyIngress(clientset kubernetes.Interface, namespace, name string, ingress *v1beta1.Ingress) error {
+	if ingress == nil {
+		return deleteIngress(clientset, namespace, name)
+	}
+
+	client := clientset.ExtensionsV1beta1().Ingresses(namespace)
+	existing, err := client.Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		glog.V(4).Infof("Creating ingress %s", name)
+		_, err = client.Create(ingress)
+		return err
+	}
+	if err != nil {
+		glog.Errorf("Unable to get ingress %s: %s", name, err)
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Spec, ingress.Spec) && reflect.DeepEqual(existing.Annotations, ingress.Annotations) {
+		return nil
+	}
+
+	existing.Annotations = ingress.Annotations
+	existing.Spec = ingress.Spec
+	glog.V(2).Infof("Updating ingress %s", name)
+	_, err = client.Update(existing)
+	return err
+}
+
+func deleteIngress(clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.ExtensionsV1beta1().Ingresses(namespace).Delete(name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete ingress %s: %s", name, err)
+		return err
+	}
+	return nil
+}
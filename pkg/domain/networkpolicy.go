@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"reflect"
+
+	"github.com/golang/glog"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/resources/networkpolicies"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateOrUpdateNetworkPoliciesForWebLogicDomain renders the NetworkPolicies
+// isolating the domain and deletes the ones it no longer needs.
+func CreateOrUpdateNetworkPoliciesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	desired := map[string]*networkingv1.NetworkPolicy{}
+	for _, policy := range networkpolicies.NewForDomain(domain) {
+		desired[policy.Name] = policy
+	}
+
+	for _, name := range networkpolicies.Names(domain) {
+		policy, ok := desired[name]
+		if !ok {
+			if err := deleteNetworkPolicy(clientset, domain.Namespace, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := applyNetworkPolicy(clientset, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteNetworkPoliciesForWebLogicDomain deletes the NetworkPolicies of the domain.
+func DeleteNetworkPoliciesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	for _, name := range networkpolicies.Names(domain) {
+		if err := deleteNetworkPolicy(clientset, domain.Namespace, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyNetworkPolicy(clientset kubernetes.Interface, policy *networkingv1.NetworkPolicy) error {
+	client := clientset.NetworkingV1().NetworkPolicies(policy.Namespace)
+	existing, err := client.Get(policy.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		glog.V(4).Infof("Creating network policy %s", policy.Name)
+		_, err = client.Create(policy)
+		return err
+	}
+	if err != nil {
+		glog.Errorf("Unable to get network policy %s: %s", policy.Name, err)
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Spec, policy.Spec) {
+		return nil
+	}
+
+	existing.Spec = policy.Spec
+	glog.V(2).Infof("Updating network policy %s", policy.Name)
+	_, err = client.Update(existing)
+	return err
+}
+
+func deleteNetworkPolicy(clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.NetworkingV1().NetworkPolicies(namespace).Delete(name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete network policy %s: %s", name, err)
+		return err
+	}
+	return nil
+}
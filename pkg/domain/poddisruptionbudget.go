@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"github.com/golang/glog"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/resources/poddisruptionbudgets"
+	"weblogic-operator/pkg/server"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateOrUpdatePodDisruptionBudgetForWebLogicDomain keeps the
+// PodDisruptionBudget of the admin server in line with the domain spec.
+func CreateOrUpdatePodDisruptionBudgetForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	if err := domain.Spec.DisruptionBudget.Validate(); err != nil {
+		glog.Errorf("Invalid disruption budget for domain %s: %s", domain.Name, err)
+		return err
+	}
+	return server.CreateOrReplacePodDisruptionBudget(clientset, poddisruptionbudgets.NewForDomain(domain))
+}
+
+// DeletePodDisruptionBudgetForWebLogicDomain deletes the PodDisruptionBudget of the admin server.
+func DeletePodDisruptionBudgetForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	return server.DeletePodDisruptionBudget(clientset, domain.Namespace, poddisruptionbudgets.NameForDomain(domain))
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/configmaps"
+	"weblogic-operator/pkg/scripts"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateConfigMapForScripts creates the ConfigMap holding the version of the
+// scripts loaded by the operator for the server pods of a domain, if missing.
+// Existing versions are never modified so running pods keep the scripts they
+// started with.
+func CreateConfigMapForScripts(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	configMap := configmaps.NewScriptsConfigMap(domain, scripts.Contents(), scripts.Hash())
+
+	_, err := clientset.CoreV1().ConfigMaps(domain.Namespace).Get(configMap.Name, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		glog.Errorf("Unable to get scripts config map for %s: %s", domain.Name, err)
+		return err
+	}
+
+	glog.V(2).Infof("Creating scripts config map for domain %s version %s", domain.Name, scripts.Hash())
+	_, err = clientset.CoreV1().ConfigMaps(domain.Namespace).Create(configMap)
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteUnusedConfigMapsForScripts deletes the ConfigMaps of older script
+// versions of a domain that no pod or ReplicaSet of the domain mounts any
+// more.
+func DeleteUnusedConfigMapsForScripts(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	configMaps, err := listConfigMapsForScripts(clientset, domain)
+	if err != nil {
+		return err
+	}
+
+	current := configmaps.ScriptsConfigMapName(domain, scripts.Hash())
+	var unused []string
+	for _, configMap := range configMaps.Items {
+		if configMap.Name != current {
+			unused = append(unused, configMap.Name)
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+
+	// Managed server pods and replica sets only carry the domain name as key
+	selectors := []string{
+		fmt.Sprintf("%s=%s", constants.WebLogicDomainLabel, domain.Name),
+		fmt.Sprintf("%s=managedserver", domain.Name),
+	}
+	used := map[string]bool{}
+	for _, selector := range selectors {
+		opts := metav1.ListOptions{LabelSelector: selector}
+		pods, err := clientset.CoreV1().Pods(domain.Namespace).List(opts)
+		if err != nil {
+			glog.Errorf("Unable to list pods of %s: %s", domain.Name, err)
+			return err
+		}
+		for _, pod := range pods.Items {
+			addConfigMapVolumes(used, pod.Spec.Volumes)
+		}
+		replicaSets, err := clientset.ExtensionsV1beta1().ReplicaSets(domain.Namespace).List(opts)
+		if err != nil {
+			glog.Errorf("Unable to list replicasets of %s: %s", domain.Name, err)
+			return err
+		}
+		for _, rs := range replicaSets.Items {
+			addConfigMapVolumes(used, rs.Spec.Template.Spec.Volumes)
+		}
+	}
+
+	for _, name := range unused {
+		if used[name] {
+			continue
+		}
+		glog.V(2).Infof("Deleting unused scripts config map %s of domain %s", name, domain.Name)
+		err = clientset.CoreV1().ConfigMaps(domain.Namespace).Delete(name, nil)
+		if err != nil && !errors.IsNotFound(err) {
+			glog.Errorf("Could not delete scripts config map %s: %s", name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteConfigMapForScripts deletes all scripts ConfigMaps of a domain, if any.
+func DeleteConfigMapForScripts(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	configMaps, err := listConfigMapsForScripts(clientset, domain)
+	if err != nil {
+		return err
+	}
+	for _, configMap := range configMaps.Items {
+		err = clientset.CoreV1().ConfigMaps(domain.Namespace).Delete(configMap.Name, nil)
+		if err != nil && !errors.IsNotFound(err) {
+			glog.Errorf("Could not delete scripts config map: %s", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func listConfigMapsForScripts(clientset kubernetes.Interface, domain *types.WebLogicDomain) (*v1.ConfigMapList, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", constants.WebLogicDomainLabel, domain.Name, constants.ScriptsVersionLabel),
+	}
+	configMaps, err := clientset.CoreV1().ConfigMaps(domain.Namespace).List(opts)
+	if err != nil {
+		glog.Errorf("Unable to list scripts config maps of %s: %s", domain.Name, err)
+		return nil, err
+	}
+	return configMaps, nil
+}
+
+func addConfigMapVolumes(names map[string]bool, volumes []v1.Volume) {
+	for _, volume := range volumes {
+		if volume.ConfigMap != nil {
+			names[volume.ConfigMap.Name] = true
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/resources/services"
+	"weblogic-operator/pkg/server"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateOrUpdateServerServicesForWebLogicDomain creates a Service for each
+// WebLogic server of the domain and one balancing across its managed servers.
+func CreateOrUpdateServerServicesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	for _, svc := range services.NewServerServicesForDomain(domain) {
+		if err := server.CreateOrUpdateService(clientset, svc); err != nil {
+			return err
+		}
+	}
+	return server.CreateOrUpdateService(clientset, services.NewClusterServiceForDomain(domain))
+}
+
+// DeleteServerServicesForWebLogicDomain deletes the per-server and cluster Services of the domain.
+func DeleteServerServicesForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	for _, svc := range services.NewServerServicesForDomain(domain) {
+		if err := server.DeleteService(clientset, domain.Namespace, svc.Name); err != nil {
+			return err
+		}
+	}
+	return server.DeleteService(clientset, domain.Namespace, services.ClusterServiceName(domain))
+}
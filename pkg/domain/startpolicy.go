@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/server"
+	"weblogic-operator/pkg/types"
+)
+
+// ApplyServerStartPolicyForWebLogicDomain scales the managed server
+// ReplicaSets of a domain to the number of servers they should run under the
+// domain's serverStartPolicy, stopping or resuming them with the domain.
+func ApplyServerStartPolicyForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=managedserver", domain.Name)}
+	replicaSets, err := clientset.ExtensionsV1beta1().ReplicaSets(domain.Namespace).List(opts)
+	if err != nil {
+		glog.Errorf("Unable to list managed server replica sets for %s: %s", domain.Name, err)
+		return err
+	}
+
+	for _, rs := range replicaSets.Items {
+		managedServer, err := server.GetServerForReplicaSet(&rs, types.ServerRESTClient)
+		if err != nil {
+			glog.Errorf("Failed to find server for replica set %s: %s", rs.Name, err)
+			return err
+		}
+		managedServer.Spec.Domain = *domain
+
+		// The autoscaler would start stopped servers again
+		_, err = server.CreateOrUpdateHorizontalPodAutoscalerForWebLogicManagedServer(clientset, managedServer)
+		if err != nil {
+			return err
+		}
+
+		desired := managedServer.ReplicaSetReplicas(rs.Spec.Replicas)
+		if rs.Spec.Replicas == nil || *rs.Spec.Replicas != desired {
+			glog.V(2).Infof("Scaling managed servers of %s to %d for server start policy %q of domain %s", rs.Name, desired, domain.Spec.ServerStartPolicy, domain.Name)
+		}
+
+		// Also records the replicas for a scale down in progress
+		err = server.ScaleReplicaSetForWebLogicManagedServer(clientset, managedServer, &rs, desired)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/secrets"
+	"weblogic-operator/pkg/resources/services"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/certs"
+)
+
+// serviceDNSNames returns the names a Service can be reached by inside the cluster.
+func serviceDNSNames(name, namespace string) []string {
+	return []string{
+		name,
+		fmt.Sprintf("%s.%s", name, namespace),
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+	}
+}
+
+// serverCertificateNames returns the names in the certificate of an identity.
+// The admin server is addressed through the domain Service and its own, the
+// managed servers through the cluster Service and the Services of every one
+// of them.
+func serverCertificateNames(domain *types.WebLogicDomain, identity string) []string {
+	var names []string
+	if identity == services.AdminServerName {
+		names = append(serviceDNSNames(domain.Name, domain.Namespace),
+			serviceDNSNames(services.ServerServiceName(domain, services.AdminServerName), domain.Namespace)...)
+	} else {
+		names = serviceDNSNames(services.ClusterServiceName(domain), domain.Namespace)
+		for _, serverName := range domain.ManagedServerNames() {
+			names = append(names, serviceDNSNames(services.ServerServiceName(domain, serverName), domain.Namespace)...)
+		}
+	}
+	return append(names, "localhost")
+}
+
+// newServerCertificate issues the certificate of an identity.
+func newServerCertificate(domain *types.WebLogicDomain, ca certs.KeyPair, identity string) (certs.KeyPair, error) {
+	names := serverCertificateNames(domain, identity)
+	return certs.NewServerCertificate(ca, names[0], names[1:])
+}
+
+// certificateNamesChanged returns true if a certificate does not hold exactly
+// the names it would be issued for now, e.g. as servers were added.
+func certificateNamesChanged(domain *types.WebLogicDomain, cert []byte, identity string) bool {
+	names, err := certs.DNSNames(cert)
+	if err != nil {
+		glog.Errorf("Unable to read certificate %s of domain %s: %s", identity, domain.Name, err)
+		return true
+	}
+	expected := serverCertificateNames(domain, identity)
+	sort.Strings(names)
+	sort.Strings(expected)
+	return !reflect.DeepEqual(names, expected)
+}
+
+// CreateOrUpdateTLSSecretForWebLogicDomain generates a CA, a keystore password
+// and certificates for the admin server and the managed servers into the TLS
+// Secret of the domain, unless the domain brings its own Secret. Existing
+// certificates are kept unless the names they are issued for changed.
+func CreateOrUpdateTLSSecretForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	if domain.Spec.TLS == nil {
+		return nil
+	}
+
+	client := clientset.CoreV1().Secrets(domain.Namespace)
+	existing, err := client.Get(domain.TLSSecretName(), metav1.GetOptions{})
+	if domain.Spec.TLS.Secret != "" {
+		if err != nil {
+			glog.Errorf("Unable to get TLS secret %s for %s: %s", domain.Spec.TLS.Secret, domain.Name, err)
+		}
+		return err
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Unable to get TLS secret for %s: %s", domain.Name, err)
+		return err
+	}
+
+	found := err == nil
+	data := map[string][]byte{}
+	if found {
+		for key, value := range existing.Data {
+			data[key] = value
+		}
+	}
+	changed := false
+
+	if len(data[constants.TLSCACertificateKey]) == 0 || len(data[constants.TLSCAKeyKey]) == 0 {
+		glog.V(2).Infof("Generating CA for domain %s", domain.Name)
+		ca, err := certs.NewCA(domain.Name + " CA")
+		if err != nil {
+			return err
+		}
+		data[constants.TLSCACertificateKey] = ca.Cert
+		data[constants.TLSCAKeyKey] = ca.Key
+
+		// Certificates of the previous CA are no longer trusted
+		for key := range data {
+			if key != constants.TLSCACertificateKey && key != constants.TLSCAKeyKey && key != constants.TLSKeystorePasswordKey {
+				delete(data, key)
+			}
+		}
+		changed = true
+	}
+	ca := certs.KeyPair{Cert: data[constants.TLSCACertificateKey], Key: data[constants.TLSCAKeyKey]}
+
+	if len(data[constants.TLSKeystorePasswordKey]) == 0 {
+		password := make([]byte, 16)
+		if _, err := rand.Read(password); err != nil {
+			return err
+		}
+		data[constants.TLSKeystorePasswordKey] = []byte(hex.EncodeToString(password))
+		changed = true
+	}
+
+	identities := []string{services.AdminServerName, secrets.ManagedServerIdentity}
+	keys := map[string]bool{
+		constants.TLSCACertificateKey:    true,
+		constants.TLSCAKeyKey:            true,
+		constants.TLSKeystorePasswordKey: true,
+	}
+	for _, identity := range identities {
+		keys[secrets.CertificateKey(identity)] = true
+		keys[secrets.PrivateKeyKey(identity)] = true
+		cert := data[secrets.CertificateKey(identity)]
+		if len(cert) > 0 && !certificateNamesChanged(domain, cert, identity) {
+			continue
+		}
+		glog.V(2).Infof("Generating certificate %s of domain %s", identity, domain.Name)
+		pair, err := newServerCertificate(domain, ca, identity)
+		if err != nil {
+			return err
+		}
+		data[secrets.CertificateKey(identity)] = pair.Cert
+		data[secrets.PrivateKeyKey(identity)] = pair.Key
+		changed = true
+	}
+
+	// Drop the certificates per managed server of earlier versions
+	for key := range data {
+		if !keys[key] {
+			delete(data, key)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	secret := secrets.NewTLSSecret(domain, data)
+	if !found {
+		glog.V(4).Infof("Creating TLS secret for domain %s", domain.Name)
+		_, err = client.Create(secret)
+		return err
+	}
+
+	existing.Data = data
+	glog.V(4).Infof("Updating TLS secret for domain %s", domain.Name)
+	_, err = client.Update(existing)
+	return err
+}
+
+// DeleteTLSSecretForWebLogicDomain deletes the generated TLS Secret of the domain, if any.
+func DeleteTLSSecretForWebLogicDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) error {
+	if domain.Spec.TLS == nil || domain.Spec.TLS.Secret != "" {
+		return nil
+	}
+
+	err := clientset.CoreV1().Secrets(domain.Namespace).Delete(domain.TLSSecretName(), nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete TLS secret: %s", err)
+		return err
+	}
+	return nil
+}
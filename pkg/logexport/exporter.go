@@ -0,0 +1,99 @@
+package logexport
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Exporter prints the records of the logs in the directories of the servers
+// of a pod, <dir>/<server>/*.log, as they are written.
+type Exporter struct {
+	// Dir holds a directory per server.
+	Dir string
+	// Domain is the name of the domain the records are labelled with.
+	Domain string
+	// Interval is how often the logs are checked for new records.
+	Interval time.Duration
+	// QuietPeriod is how long the logs must not grow after the exporter is
+	// stopped before it exits.
+	QuietPeriod time.Duration
+	// GracePeriod bounds how long the exporter follows the logs after it is
+	// stopped.
+	GracePeriod time.Duration
+
+	encoder *json.Encoder
+	tailers map[string]*tailer
+	started bool
+}
+
+// NewExporter returns an Exporter printing the logs in dir to out.
+func NewExporter(dir, domain string, interval, quietPeriod, gracePeriod time.Duration, out io.Writer) *Exporter {
+	// Keep the angle brackets of WebLogic messages readable
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	return &Exporter{
+		Dir:         dir,
+		Domain:      domain,
+		Interval:    interval,
+		QuietPeriod: quietPeriod,
+		GracePeriod: gracePeriod,
+		encoder:     encoder,
+		tailers:     map[string]*tailer{},
+	}
+}
+
+func (e *Exporter) emit(record *Record) {
+	record.Domain = e.Domain
+	if err := e.encoder.Encode(record); err != nil {
+		glog.Errorf("Unable to print a record of %s: %s", record.Server, err)
+	}
+}
+
+// poll follows new logs and prints the records appended to all of them,
+// returning true if anything was read. Logs found by the first poll were
+// printed before the exporter started, so only what is appended to them is.
+func (e *Exporter) poll() bool {
+	paths, err := filepath.Glob(filepath.Join(e.Dir, "*", "*.log"))
+	if err != nil {
+		glog.Errorf("Unable to list the logs in %s: %s", e.Dir, err)
+		return false
+	}
+	for _, path := range paths {
+		if _, found := e.tailers[path]; !found {
+			glog.V(2).Infof("Exporting %s", path)
+			e.tailers[path] = newTailer(path, !e.started)
+		}
+	}
+	e.started = true
+
+	read := false
+	for _, tailer := range e.tailers {
+		read = tailer.poll(e.emit) || read
+	}
+	return read
+}
+
+// Run prints the logs until stopChan is closed. As the server may still be
+// shutting down, it then follows them until they did not grow for the quiet
+// period or the grace period is over, and prints the records read so far.
+func (e *Exporter) Run(stopChan <-chan struct{}) {
+	wait.Until(func() { e.poll() }, e.Interval, stopChan)
+
+	deadline := time.Now().Add(e.GracePeriod)
+	lastRead := time.Now()
+	for time.Now().Before(deadline) && time.Since(lastRead) < e.QuietPeriod {
+		time.Sleep(e.Interval)
+		if e.poll() {
+			lastRead = time.Now()
+		}
+	}
+
+	for _, tailer := range e.tailers {
+		tailer.close(e.emit)
+	}
+}
@@ -0,0 +1,81 @@
+// Package logexport prints the logs of WebLogic servers as JSON records, one
+// per line, for the log pipeline of the cluster.
+package logexport
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of the exported logs.
+const (
+	ServerLog = "server"
+	DomainLog = "domain"
+	AccessLog = "access"
+)
+
+// recordStart begins every record of a server or domain log.
+const recordStart = "####<"
+
+// Record is a log entry as printed to stdout.
+type Record struct {
+	Time      string `json:"time,omitempty"`
+	Domain    string `json:"domain,omitempty"`
+	Server    string `json:"server"`
+	Log       string `json:"log"`
+	Severity  string `json:"severity,omitempty"`
+	Subsystem string `json:"subsystem,omitempty"`
+	MessageID string `json:"messageId,omitempty"`
+	Message   string `json:"message"`
+}
+
+// logKind returns the kind of the log at path, which is in the directory named
+// after its server. Other logs than the server and access logs of the server
+// are the domain log of the admin server.
+func logKind(path string) string {
+	server := filepath.Base(filepath.Dir(path))
+	switch filepath.Base(path) {
+	case server + ".log":
+		return ServerLog
+	case "access.log":
+		return AccessLog
+	default:
+		return DomainLog
+	}
+}
+
+// parseRecord parses a record of a server or domain log such as
+//
+//	####<Mar 1, 2018 10:00:00,000 AM UTC> <Notice> <WebLogicServer> <host>
+//	<AdminServer> <main> <<WLS Kernel>> <> <> <1519898400000>
+//	<[severity-value: 32] [rid: 0] > <BEA-000365> <Server state changed to RUNNING.>
+//
+// on a single line. Older servers omit the supplemental attributes in square
+// brackets. Text not in this format is kept as the message.
+func parseRecord(text string, record *Record) {
+	record.Message = text
+	if !strings.HasPrefix(text, recordStart) {
+		return
+	}
+
+	body := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(text, recordStart)), ">")
+	fields := strings.SplitN(body, "> <", 13)
+	if len(fields) < 12 {
+		return
+	}
+
+	record.Severity = fields[1]
+	record.Subsystem = fields[2]
+	if millis, err := strconv.ParseInt(fields[9], 10, 64); err == nil {
+		record.Time = time.Unix(0, millis*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano)
+	}
+	if len(fields) == 13 && strings.HasPrefix(fields[10], "[") {
+		record.MessageID = fields[11]
+		record.Message = fields[12]
+	} else {
+		record.MessageID = fields[10]
+		record.Message = strings.Join(fields[11:], "> <")
+	}
+}
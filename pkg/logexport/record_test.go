@@ -0,0 +1,107 @@
+package logexport
+
+import (
+	"testing"
+)
+
+func TestParseRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Record
+	}{
+		{
+			name: "supplemental attributes",
+			text: "####<Mar 1, 2018 10:00:00,000 AM UTC> <Notice> <WebLogicServer> <host> <AdminServer> <main> <<WLS Kernel>> <> <> <1519898400000> <[severity-value: 32] [rid: 0] > <BEA-000365> <Server state changed to RUNNING.>",
+			want: Record{
+				Time:      "2018-03-01T10:00:00Z",
+				Severity:  "Notice",
+				Subsystem: "WebLogicServer",
+				MessageID: "BEA-000365",
+				Message:   "Server state changed to RUNNING.",
+			},
+		},
+		{
+			name: "no supplemental attributes",
+			text: "####<Mar 1, 2018 10:00:00,123 AM UTC> <Info> <Management> <host> <managedserver-0> <main> <<WLS Kernel>> <> <> <1519898400123> <BEA-141107> <Version: WebLogic Server 12.2.1.2.0>",
+			want: Record{
+				Time:      "2018-03-01T10:00:00.123Z",
+				Severity:  "Info",
+				Subsystem: "Management",
+				MessageID: "BEA-141107",
+				Message:   "Version: WebLogic Server 12.2.1.2.0",
+			},
+		},
+		{
+			name: "message with brackets and supplemental attributes",
+			text: "####<Mar 1, 2018 10:00:00,000 AM UTC> <Warning> <Deployer> <host> <AdminServer> <main> <<WLS Kernel>> <> <> <1519898400000> <[severity-value: 16] [rid: 0] > <BEA-149078> <Stack of <app> <module> failed.>",
+			want: Record{
+				Time:      "2018-03-01T10:00:00Z",
+				Severity:  "Warning",
+				Subsystem: "Deployer",
+				MessageID: "BEA-149078",
+				Message:   "Stack of <app> <module> failed.",
+			},
+		},
+		{
+			name: "message with brackets and no supplemental attributes",
+			text: "####<Mar 1, 2018 10:00:00,000 AM UTC> <Warning> <Deployer> <host> <AdminServer> <main> <<WLS Kernel>> <> <> <1519898400000> <BEA-149078> <Stack of <app> <module> failed.>",
+			want: Record{
+				Time:      "2018-03-01T10:00:00Z",
+				Severity:  "Warning",
+				Subsystem: "Deployer",
+				MessageID: "BEA-149078",
+				Message:   "Stack of <app> <module> failed.",
+			},
+		},
+		{
+			name: "multiple lines",
+			text: "####<Mar 1, 2018 10:00:00,000 AM UTC> <Error> <HTTP> <host> <managedserver-0> <[ACTIVE] ExecuteThread: '0'> <<anonymous>> <> <> <1519898400000> <[severity-value: 8] [rid: 0] > <BEA-101020> <Servlet failed with an Exception\njava.lang.NullPointerException\n\tat example.Servlet.service(Servlet.java:10)>",
+			want: Record{
+				Time:      "2018-03-01T10:00:00Z",
+				Severity:  "Error",
+				Subsystem: "HTTP",
+				MessageID: "BEA-101020",
+				Message:   "Servlet failed with an Exception\njava.lang.NullPointerException\n\tat example.Servlet.service(Servlet.java:10)",
+			},
+		},
+		{
+			name: "not a record",
+			text: "<Mar 1, 2018 10:00:00 AM UTC> <Notice> <WebLogicServer> <BEA-000365> <Server state changed to RUNNING.>",
+			want: Record{
+				Message: "<Mar 1, 2018 10:00:00 AM UTC> <Notice> <WebLogicServer> <BEA-000365> <Server state changed to RUNNING.>",
+			},
+		},
+		{
+			name: "too few fields",
+			text: "####<Mar 1, 2018 10:00:00,000 AM UTC> <Notice> <WebLogicServer>",
+			want: Record{
+				Message: "####<Mar 1, 2018 10:00:00,000 AM UTC> <Notice> <WebLogicServer>",
+			},
+		},
+	}
+	for _, test := range tests {
+		var record Record
+		parseRecord(test.text, &record)
+		if record != test.want {
+			t.Errorf("%s: parseRecord() = %+v, want %+v", test.name, record, test.want)
+		}
+	}
+}
+
+func TestLogKind(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/u01/oracle/logs/AdminServer/AdminServer.log", ServerLog},
+		{"/u01/oracle/logs/AdminServer/base_domain.log", DomainLog},
+		{"/u01/oracle/logs/managedserver-0/managedserver-0.log", ServerLog},
+		{"/u01/oracle/logs/managedserver-0/access.log", AccessLog},
+	}
+	for _, test := range tests {
+		if got := logKind(test.path); got != test.want {
+			t.Errorf("logKind(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package logexport
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// tailer follows a log file across rotations, collecting the lines of a
+// record until the next one starts.
+type tailer struct {
+	path    string
+	server  string
+	kind    string
+	fromEnd bool
+	file    *os.File
+	partial []byte
+	pending []string
+}
+
+// newTailer returns a tailer for the log at path, skipping what it holds
+// when first opened if fromEnd is true.
+func newTailer(path string, fromEnd bool) *tailer {
+	return &tailer{
+		path:    path,
+		server:  filepath.Base(filepath.Dir(path)),
+		kind:    logKind(path),
+		fromEnd: fromEnd,
+	}
+}
+
+// poll reads what was appended to the log since the last poll and emits the
+// complete records, returning true if anything was read. A record still
+// pending after a poll that read nothing is emitted as well, as WebLogic
+// writes records whole.
+func (t *tailer) poll(emit func(*Record)) bool {
+	if t.file == nil {
+		file, err := os.Open(t.path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				glog.Errorf("Unable to open %s: %s", t.path, err)
+			}
+			return false
+		}
+		t.file = file
+
+		// Skip what an earlier run of the exporter printed. Rotated logs are
+		// read from their start.
+		if t.fromEnd {
+			t.fromEnd = false
+			if _, err := t.file.Seek(0, io.SeekEnd); err != nil {
+				glog.Errorf("Unable to skip the records in %s: %s", t.path, err)
+			}
+		}
+	}
+
+	read := t.read(emit)
+
+	// Finish the rotated file, then continue with the new one from its start
+	info, err := os.Stat(t.path)
+	current, statErr := t.file.Stat()
+	if err == nil && statErr == nil && !os.SameFile(info, current) {
+		read = t.read(emit) || read
+		t.flushPartial(emit)
+		t.flush(emit)
+		t.file.Close()
+		t.file = nil
+		glog.V(2).Infof("%s was rotated", t.path)
+		return read
+	}
+	if err == nil && statErr == nil {
+		offset, seekErr := t.file.Seek(0, io.SeekCurrent)
+		if seekErr == nil && info.Size() < offset {
+			glog.V(2).Infof("%s was truncated", t.path)
+			t.file.Seek(0, io.SeekStart)
+			t.partial = nil
+		}
+	}
+
+	if !read {
+		t.flush(emit)
+	}
+	return read
+}
+
+// read reads the log up to its end, returning true if anything was read.
+func (t *tailer) read(emit func(*Record)) bool {
+	buffer := make([]byte, 32*1024)
+	read := false
+	for {
+		n, err := t.file.Read(buffer)
+		if n > 0 {
+			read = true
+			t.partial = append(t.partial, buffer[:n]...)
+			t.lines(emit)
+		}
+		if err != nil {
+			if err != io.EOF {
+				glog.Errorf("Unable to read %s: %s", t.path, err)
+			}
+			return read
+		}
+	}
+}
+
+// lines handles the complete lines read so far.
+func (t *tailer) lines(emit func(*Record)) {
+	for {
+		end := bytes.IndexByte(t.partial, '\n')
+		if end < 0 {
+			return
+		}
+		t.line(strings.TrimRight(string(t.partial[:end]), "\r"), emit)
+		t.partial = t.partial[end+1:]
+	}
+}
+
+func (t *tailer) line(line string, emit func(*Record)) {
+	// Access logs have a line per request
+	if t.kind == AccessLog {
+		if line != "" {
+			emit(&Record{Server: t.server, Log: t.kind, Message: line})
+		}
+		return
+	}
+	if strings.HasPrefix(line, recordStart) {
+		t.flush(emit)
+	}
+	t.pending = append(t.pending, line)
+}
+
+// flushPartial handles a last line without a line break.
+func (t *tailer) flushPartial(emit func(*Record)) {
+	if len(t.partial) > 0 {
+		t.line(string(t.partial), emit)
+		t.partial = nil
+	}
+}
+
+// flush emits the pending record, if any.
+func (t *tailer) flush(emit func(*Record)) {
+	if len(t.pending) == 0 {
+		return
+	}
+	record := &Record{Server: t.server, Log: t.kind}
+	parseRecord(strings.Join(t.pending, "\n"), record)
+	t.pending = nil
+	emit(record)
+}
+
+func (t *tailer) close(emit func(*Record)) {
+	if t.file != nil {
+		t.read(emit)
+		t.file.Close()
+		t.file = nil
+	}
+	t.flushPartial(emit)
+	t.flush(emit)
+}
@@ -0,0 +1,150 @@
+package logexport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// serverLog returns the path of the server log of a server in a temporary
+// directory, to be removed by the caller.
+func serverLog(t *testing.T) (string, string) {
+	dir, err := ioutil.TempDir("", "logexport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "AdminServer"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir, filepath.Join(dir, "AdminServer", "AdminServer.log")
+}
+
+func record(messageID, message string) string {
+	return fmt.Sprintf("####<Mar 1, 2018 10:00:00,000 AM UTC> <Notice> <WebLogicServer> <host> <AdminServer> <main> <<WLS Kernel>> <> <> <1519898400000> <[severity-value: 32] [rid: 0] > <%s> <%s>", messageID, message)
+}
+
+func appendLog(t *testing.T, path, text string) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// pollMessages polls the tailer and returns the messages of the records it
+// emitted.
+func pollMessages(tailer *tailer) []string {
+	var messages []string
+	tailer.poll(func(r *Record) {
+		messages = append(messages, r.Message)
+	})
+	return messages
+}
+
+func expectMessages(t *testing.T, step string, got []string, want ...string) {
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: emitted %q, want %q", step, got, want)
+	}
+}
+
+func TestTailerMultiLineRecords(t *testing.T) {
+	dir, path := serverLog(t)
+	defer os.RemoveAll(dir)
+
+	appendLog(t, path, record("BEA-000001", "Failed\njava.lang.Exception\n\tat Example.main(Example.java:1)")+"\n")
+	tailer := newTailer(path, false)
+	defer tailer.close(func(*Record) {})
+
+	// The record may go on until the next one starts
+	expectMessages(t, "first poll", pollMessages(tailer))
+
+	appendLog(t, path, record("BEA-000002", "Second")+"\n")
+	expectMessages(t, "next record", pollMessages(tailer),
+		"Failed\njava.lang.Exception\n\tat Example.main(Example.java:1)")
+
+	// Nothing was appended, so the pending record is complete
+	expectMessages(t, "quiet poll", pollMessages(tailer), "Second")
+}
+
+func TestTailerFromEnd(t *testing.T) {
+	dir, path := serverLog(t)
+	defer os.RemoveAll(dir)
+
+	appendLog(t, path, record("BEA-000001", "Printed before")+"\n")
+	tailer := newTailer(path, true)
+	defer tailer.close(func(*Record) {})
+
+	expectMessages(t, "first poll", pollMessages(tailer))
+	expectMessages(t, "quiet poll", pollMessages(tailer))
+
+	appendLog(t, path, record("BEA-000002", "Appended")+"\n")
+	expectMessages(t, "append", pollMessages(tailer))
+	expectMessages(t, "quiet poll", pollMessages(tailer), "Appended")
+}
+
+func TestTailerRotationInRecord(t *testing.T) {
+	dir, path := serverLog(t)
+	defer os.RemoveAll(dir)
+
+	appendLog(t, path, record("BEA-000001", "Complete")+"\n")
+	tailer := newTailer(path, true)
+	defer tailer.close(func(*Record) {})
+	expectMessages(t, "first poll", pollMessages(tailer))
+
+	// The record and its last line are still being written when rotated
+	text := record("BEA-000002", "Failed\njava.lang.Exception")
+	split := strings.Index(text, "java.lang.") + len("java.lang.")
+	appendLog(t, path, text[:split])
+	expectMessages(t, "start of record", pollMessages(tailer))
+	appendLog(t, path, text[split:])
+	if err := os.Rename(path, path+"00001"); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, path, record("BEA-000003", "After rotation")+"\n")
+
+	// The rotated file is finished, the new one read from its start
+	expectMessages(t, "rotation", pollMessages(tailer), "Failed\njava.lang.Exception")
+	expectMessages(t, "new file", pollMessages(tailer))
+	expectMessages(t, "quiet poll", pollMessages(tailer), "After rotation")
+}
+
+func TestTailerTruncation(t *testing.T) {
+	dir, path := serverLog(t)
+	defer os.RemoveAll(dir)
+
+	appendLog(t, path, record("BEA-000001", "Before truncation")+"\n")
+	tailer := newTailer(path, false)
+	defer tailer.close(func(*Record) {})
+	expectMessages(t, "first poll", pollMessages(tailer))
+
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, path, record("BEA-000002", "After")+"\n")
+
+	// Nothing new is found at the old offset, the log is read again from its start
+	expectMessages(t, "truncation", pollMessages(tailer), "Before truncation")
+	expectMessages(t, "start", pollMessages(tailer))
+	expectMessages(t, "quiet poll", pollMessages(tailer), "After")
+}
+
+func TestTailerAccessLog(t *testing.T) {
+	dir, _ := serverLog(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "AdminServer", "access.log")
+
+	appendLog(t, path, "10.0.0.1 - - [01/Mar/2018:10:00:00 +0000] \"GET / HTTP/1.1\" 200 10\n\n")
+	tailer := newTailer(path, false)
+	defer tailer.close(func(*Record) {})
+
+	// Every line is a record of its own
+	expectMessages(t, "first poll", pollMessages(tailer),
+		"10.0.0.1 - - [01/Mar/2018:10:00:00 +0000] \"GET / HTTP/1.1\" 200 10")
+}
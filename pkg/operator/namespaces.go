@@ -0,0 +1,244 @@
+package operator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"weblogic-operator/pkg/domain"
+	"weblogic-operator/pkg/server"
+)
+
+// NamespaceConfig selects the namespaces the operator manages, either by name
+// or by label. When neither is set all namespaces are managed.
+type NamespaceConfig struct {
+	// Namespaces lists the namespaces to manage.
+	Namespaces []string
+	// Selector is a label selector matching the namespaces to manage. Watching
+	// namespaces is then the only cluster-wide permission the operator needs.
+	Selector string
+}
+
+// Validate returns an error if both namespaces and a selector are given, or
+// the selector cannot be parsed.
+func (c NamespaceConfig) Validate() error {
+	if len(c.Namespaces) > 0 && c.Selector != "" {
+		return fmt.Errorf("namespaces and a namespace selector cannot both be given")
+	}
+	if c.Selector != "" {
+		if _, err := labels.Parse(c.Selector); err != nil {
+			return fmt.Errorf("invalid namespace selector %q: %s", c.Selector, err)
+		}
+	}
+	return nil
+}
+
+// namespaceControllers runs a server and a domain controller per managed
+// namespace, starting and stopping them as namespaces matching the selector
+// come and go.
+type namespaceControllers struct {
+	clientSet        kubernetes.Interface
+	serverRESTClient *rest.RESTClient
+	domainRESTClient *rest.RESTClient
+	resyncPeriod     time.Duration
+	config           NamespaceConfig
+
+	mutex   sync.Mutex
+	running map[string]chan struct{}
+}
+
+func newNamespaceControllers(clientSet kubernetes.Interface, serverRESTClient, domainRESTClient *rest.RESTClient, resyncPeriod time.Duration, config NamespaceConfig) *namespaceControllers {
+	return &namespaceControllers{
+		clientSet:        clientSet,
+		serverRESTClient: serverRESTClient,
+		domainRESTClient: domainRESTClient,
+		resyncPeriod:     resyncPeriod,
+		config:           config,
+		running:          map[string]chan struct{}{},
+	}
+}
+
+// start runs the controllers of a namespace unless they are running already.
+// Failures are logged, starting the namespace again retries.
+func (n *namespaceControllers) start(namespace string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.startLocked(namespace)
+}
+
+// startLocked is start for callers holding the mutex.
+func (n *namespaceControllers) startLocked(namespace string) {
+	if _, found := n.running[namespace]; found {
+		return
+	}
+
+	serverController, err := server.NewController(n.clientSet, n.serverRESTClient, n.resyncPeriod, namespace)
+	if err != nil {
+		glog.Errorf("Failed to create the server controller for namespace %s: %s", namespace, err)
+		return
+	}
+	domainController, err := domain.NewController(n.clientSet, n.domainRESTClient, n.resyncPeriod, namespace)
+	if err != nil {
+		glog.Errorf("Failed to create the domain controller for namespace %s: %s", namespace, err)
+		return
+	}
+
+	if namespace == v1.NamespaceAll {
+		glog.Infof("Managing all namespaces")
+	} else {
+		glog.Infof("Managing namespace %s", namespace)
+	}
+	stopChan := make(chan struct{})
+	n.running[namespace] = stopChan
+	go serverController.Run(stopChan)
+	go domainController.Run(stopChan)
+}
+
+// stop stops the controllers of a namespace, if running.
+func (n *namespaceControllers) stop(namespace string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.stopLocked(namespace)
+}
+
+// stopLocked is stop for callers holding the mutex.
+func (n *namespaceControllers) stopLocked(namespace string) {
+	stopChan, found := n.running[namespace]
+	if !found {
+		return
+	}
+	glog.Infof("No longer managing namespace %s", namespace)
+	close(stopChan)
+	delete(n.running, namespace)
+}
+
+// manages returns true if the controllers of the given namespace are running.
+func (n *namespaceControllers) manages(namespace string) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if _, found := n.running[v1.NamespaceAll]; found {
+		return true
+	}
+	_, found := n.running[namespace]
+	return found
+}
+
+// runningNamespaces returns the namespaces whose controllers are running.
+// The caller holds the mutex.
+func (n *namespaceControllers) runningNamespaces() []string {
+	namespaces := make([]string, 0, len(n.running))
+	for namespace := range n.running {
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+func (n *namespaceControllers) stopAll() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, namespace := range n.runningNamespaces() {
+		n.stopLocked(namespace)
+	}
+}
+
+// setResyncPeriod restarts the running controllers with the given resync
+// period. The mutex is held throughout, so namespaces deleted meanwhile are
+// not started again.
+func (n *namespaceControllers) setResyncPeriod(resyncPeriod time.Duration) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.resyncPeriod = resyncPeriod
+
+	glog.Infof("Restarting controllers to resync every %s", resyncPeriod)
+	for _, namespace := range n.runningNamespaces() {
+		n.stopLocked(namespace)
+		n.startLocked(namespace)
+	}
+}
+
+func (n *namespaceControllers) onNamespaceAdd(obj interface{}) {
+	namespace := obj.(*v1.Namespace)
+	n.start(namespace.Name)
+}
+
+// onNamespaceUpdate starts the controllers of a namespace that failed to
+// start before, on every resync.
+func (n *namespaceControllers) onNamespaceUpdate(old, cur interface{}) {
+	n.onNamespaceAdd(cur)
+}
+
+func (n *namespaceControllers) onNamespaceDelete(obj interface{}) {
+	namespace, ok := obj.(*v1.Namespace)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if namespace, ok = tombstone.Obj.(*v1.Namespace); !ok {
+			return
+		}
+	}
+	n.stop(namespace.Name)
+}
+
+// watchNamespaces returns an informer starting the controllers of namespaces
+// that come to match the selector and stopping those of namespaces that are
+// deleted or no longer match.
+func (n *namespaceControllers) watchNamespaces() cache.Controller {
+	_, controller := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = n.config.Selector
+				return n.clientSet.CoreV1().Namespaces().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = n.config.Selector
+				return n.clientSet.CoreV1().Namespaces().Watch(options)
+			},
+		},
+		&v1.Namespace{},
+		n.resyncPeriod,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    n.onNamespaceAdd,
+			UpdateFunc: n.onNamespaceUpdate,
+			DeleteFunc: n.onNamespaceDelete,
+		})
+	return controller
+}
+
+// startListed starts the controllers of the namespaces given by name, or of
+// all namespaces.
+func (n *namespaceControllers) startListed() {
+	if len(n.config.Namespaces) == 0 {
+		n.start(v1.NamespaceAll)
+		return
+	}
+	for _, namespace := range n.config.Namespaces {
+		n.start(namespace)
+	}
+}
+
+// Run runs the controllers of the managed namespaces until stopChan is
+// closed. Namespaces whose controllers failed to start are retried every
+// resync period.
+func (n *namespaceControllers) Run(stopChan <-chan struct{}) {
+	if n.config.Selector != "" {
+		glog.Infof("Managing namespaces matching %s", n.config.Selector)
+		go n.watchNamespaces().Run(stopChan)
+	} else {
+		go wait.Until(n.startListed, n.resyncPeriod, stopChan)
+	}
+
+	<-stopChan
+	n.stopAll()
+}
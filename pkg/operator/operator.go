@@ -4,7 +4,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/golang/glog"
 
@@ -12,13 +11,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
-	"io"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/config"
 	"weblogic-operator/pkg/controllers"
-	"weblogic-operator/pkg/domain"
-	"weblogic-operator/pkg/server"
+	"weblogic-operator/pkg/scripts"
 	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/webhook"
 )
 
 // Operator operates things!
@@ -26,8 +25,20 @@ type Operator struct {
 	Controllers []controllers.Controller
 }
 
-// NewWeblogicOperator instantiates a Weblogic Operator.
-func NewWeblogicOperator(restConfig *rest.Config) (*Operator, error) {
+// NewWeblogicOperator instantiates a Weblogic Operator managing the namespaces
+// selected by namespaceConfig. Settings are read from the configuration file
+// at configPath, if given, which is then watched for changes. The WLDF scaling
+// webhook is served alongside the controllers when it is enabled.
+func NewWeblogicOperator(restConfig *rest.Config, configPath string, namespaceConfig NamespaceConfig, webhookConfig webhook.Config) (*Operator, error) {
+	var configWatcher *config.Watcher
+	if configPath != "" {
+		var err error
+		configWatcher, err = config.NewWatcher(configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	managedServerRESTClient, err := types.NewManagedServerRESTClient(restConfig)
 	domainRESTClient, err := types.NewDomainRESTClient(restConfig)
 	if err != nil {
@@ -40,18 +51,32 @@ func NewWeblogicOperator(restConfig *rest.Config) (*Operator, error) {
 		return nil, err
 	}
 
-	serverController, err := server.NewController(clientSet, managedServerRESTClient, 30*time.Second, v1.NamespaceAll)
-	domainController, err := domain.NewController(clientSet, domainRESTClient, 30*time.Second, v1.NamespaceAll)
+	err = namespaceConfig.Validate()
 	if err != nil {
 		return nil, err
 	}
+	managedNamespaces := newNamespaceControllers(clientSet, managedServerRESTClient, domainRESTClient, config.Current().ResyncPeriod.Duration, namespaceConfig)
 
-	err = copyScripts()
+	// Delivered to the domains through ConfigMaps
+	err = scripts.Load(config.Current().ScriptsDir)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewWithControllers([]controllers.Controller{serverController, domainController}), nil
+	operatorControllers := []controllers.Controller{managedNamespaces}
+	if configWatcher != nil {
+		configWatcher.OnResyncPeriodChange = managedNamespaces.setResyncPeriod
+		operatorControllers = append(operatorControllers, configWatcher)
+	}
+	if webhookConfig.Enabled() {
+		scalingWebhook, err := webhook.NewScalingWebhook(webhookConfig, clientSet, managedServerRESTClient, managedNamespaces.manages)
+		if err != nil {
+			return nil, err
+		}
+		operatorControllers = append(operatorControllers, scalingWebhook)
+	}
+
+	return NewWithControllers(operatorControllers), nil
 }
 
 func NewPersistentVolume() v1.PersistentVolume {
@@ -122,42 +147,3 @@ func (o *Operator) Run() {
 		close(stopChan)
 	}
 }
-
-func copyScripts() error {
-	glog.Infof("Copying scripts to %s...", "/u01/oracle/user_projects")
-
-	source := "/scripts"
-	dest := "/u01/oracle/user_projects"
-
-	directory, _ := os.Open(source)
-	objects, _ := directory.Readdir(-1)
-
-	for _, obj := range objects {
-		sourcefilepointer := source + "/" + obj.Name()
-		destinationfilepointer := dest + "/" + obj.Name()
-
-		sourcefile, err := os.Open(sourcefilepointer)
-		if err != nil {
-			return err
-		}
-
-		defer sourcefile.Close()
-
-		destfile, err := os.Create(destinationfilepointer)
-		if err != nil {
-			return err
-		}
-
-		defer destfile.Close()
-
-		_, err = io.Copy(destfile, sourcefile)
-		if err == nil {
-			_, err := os.Stat(source)
-			if err != nil {
-				err = os.Chmod(dest, os.FileMode(777))
-			}
-
-		}
-	}
-	return nil
-}
@@ -0,0 +1,58 @@
+package configmaps
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// DomainModelConfigMapName returns the name of the ConfigMap holding the
+// generated domain model script of a WebLogicDomain.
+func DomainModelConfigMapName(domain *types.WebLogicDomain) string {
+	return domain.Name + "-domain-model"
+}
+
+// NewDomainModelConfigMap creates the ConfigMap mounted into the admin server
+// pod that carries the WLST script generated from the domain model.
+func NewDomainModelConfigMap(domain *types.WebLogicDomain, script string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DomainModelConfigMapName(domain),
+			Namespace: domain.Namespace,
+			Labels: map[string]string{
+				constants.WebLogicDomainLabel: domain.Name,
+			},
+		},
+		Data: map[string]string{
+			constants.DomainModelScriptKey: script,
+		},
+	}
+}
+
+// ScriptsConfigMapName returns the name of the ConfigMap holding the given
+// version of the scripts run in the server pods of a WebLogicDomain. Each
+// version gets its own ConfigMap so running pods keep a consistent set.
+func ScriptsConfigMapName(domain *types.WebLogicDomain, version string) string {
+	return domain.Name + "-scripts-" + version
+}
+
+// NewScriptsConfigMap creates the ConfigMap mounted into every server pod of
+// the domain that carries the start, stop and setup scripts, labelled and
+// annotated with their version.
+func NewScriptsConfigMap(domain *types.WebLogicDomain, scripts map[string]string, version string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScriptsConfigMapName(domain, version),
+			Namespace: domain.Namespace,
+			Labels: map[string]string{
+				constants.WebLogicDomainLabel: domain.Name,
+				constants.ScriptsVersionLabel: version,
+			},
+			Annotations: map[string]string{
+				constants.ScriptsHashAnnotation: version,
+			},
+		},
+		Data: scripts,
+	}
+}
@@ -1,32 +1,52 @@
 package horizontalpodautoscalers
 
 import (
-	"k8s.io/api/autoscaling/v1"
+	"k8s.io/api/autoscaling/v2alpha1"
+	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"weblogic-operator/pkg/constants"
 	"weblogic-operator/pkg/types"
 )
 
-// NewForHorizontalPodAutoscaling creates a new HPA for the given WebLogicManagedServer - managedserver.
-func NewForHorizontalPodAutoscaling(server *types.WebLogicManagedServer, serviceName string) *v1.HorizontalPodAutoscaler {
-	var minReplicas int32 = 1
-	var maxReplicas int32 = int32(server.Spec.Domain.Spec.ManagedServerCount)
+// Name returns the name of the HPA of the given WebLogicManagedServer.
+func Name(server *types.WebLogicManagedServer) string {
+	return server.Name + "-scaler"
+}
+
+// NewForServer creates a new HPA for the given WebLogicManagedServer.
+func NewForServer(server *types.WebLogicManagedServer) *v2alpha1.HorizontalPodAutoscaler {
+	minReplicas, maxReplicas := server.AutoscalerReplicas()
 	var targetCPUUtilization int32 = 50
-	hpaMinReplicas := &minReplicas
-	hpaTargetCPUUtilization := &targetCPUUtilization
+	metrics := []v2alpha1.MetricSpec{{
+		Type: v2alpha1.ResourceMetricSourceType,
+		Resource: &v2alpha1.ResourceMetricSource{
+			Name:                     v1.ResourceCPU,
+			TargetAverageUtilization: &targetCPUUtilization,
+		},
+	}}
 
-	hpa := &v1.HorizontalPodAutoscaler{
+	if settings := server.Spec.Autoscaling; settings != nil && len(settings.Metrics) > 0 {
+		metrics = settings.Metrics
+	}
+
+	hpa := &v2alpha1.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: constants.HorizontalPodAutoscalerName,
+			Namespace: server.Namespace,
+			Name:      Name(server),
+			Labels: map[string]string{
+				constants.WebLogicManagedServerLabel: server.Name,
+				server.Spec.DomainName:               "managedserver",
+			},
 		},
-		Spec: v1.HorizontalPodAutoscalerSpec{
-			ScaleTargetRef: v1.CrossVersionObjectReference{
-				Kind: constants.HorizontalPodAutoscalerKind,
-				Name: server.Name,
+		Spec: v2alpha1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: v2alpha1.CrossVersionObjectReference{
+				APIVersion: constants.HorizontalPodAutoscalerAPIVersion,
+				Kind:       constants.HorizontalPodAutoscalerKind,
+				Name:       server.Name,
 			},
-			MinReplicas:                    hpaMinReplicas,
-			MaxReplicas:                    maxReplicas,
-			TargetCPUUtilizationPercentage: hpaTargetCPUUtilization,
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
 		},
 	}
 
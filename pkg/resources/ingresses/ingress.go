@@ -0,0 +1,120 @@
+package ingresses
+
+import (
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"weblogic-operator/pkg/resources/services"
+	"weblogic-operator/pkg/types"
+)
+
+const (
+	ingressClassAnnotation  = "kubernetes.io/ingress.class"
+	defaultPath             = "/"
+	defaultAdminConsolePath = "/console"
+)
+
+// Name returns the name of the Ingress routing to the managed servers of a domain.
+func Name(domain *types.WebLogicDomain) string {
+	return domain.Name + "-ingress"
+}
+
+// AdminConsoleName returns the name of the Ingress routing to the admin console of a domain.
+func AdminConsoleName(domain *types.WebLogicDomain) string {
+	return domain.Name + "-admin-console"
+}
+
+func newIngress(domain *types.WebLogicDomain, name string, hosts []string, path string, backend v1beta1.IngressBackend) *v1beta1.Ingress {
+	settings := domain.Spec.Ingress
+
+	annotations := map[string]string{}
+	for key, value := range settings.Annotations {
+		annotations[key] = value
+	}
+	if settings.Class != "" {
+		annotations[ingressClassAnnotation] = settings.Class
+	}
+
+	paths := v1beta1.HTTPIngressRuleValue{
+		Paths: []v1beta1.HTTPIngressPath{{
+			Path:    path,
+			Backend: backend,
+		}},
+	}
+
+	var rules []v1beta1.IngressRule
+	if len(hosts) == 0 {
+		rules = append(rules, v1beta1.IngressRule{
+			IngressRuleValue: v1beta1.IngressRuleValue{HTTP: &paths},
+		})
+	}
+	for _, host := range hosts {
+		rules = append(rules, v1beta1.IngressRule{
+			Host:             host,
+			IngressRuleValue: v1beta1.IngressRuleValue{HTTP: &paths},
+		})
+	}
+
+	ingress := &v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				domain.Name: "ingress",
+			},
+			Annotations: annotations,
+			Name:        name,
+			Namespace:   domain.Namespace,
+		},
+		Spec: v1beta1.IngressSpec{
+			Rules: rules,
+		},
+	}
+
+	if settings.TLSSecret != "" {
+		ingress.Spec.TLS = []v1beta1.IngressTLS{{
+			Hosts:      hosts,
+			SecretName: settings.TLSSecret,
+		}}
+	}
+	return ingress
+}
+
+// NewForDomain returns an Ingress routing to the cluster Service of the
+// domain, or nil if the domain has no ingress.
+func NewForDomain(domain *types.WebLogicDomain) *v1beta1.Ingress {
+	if domain.Spec.Ingress == nil {
+		return nil
+	}
+
+	path := domain.Spec.Ingress.Path
+	if path == "" {
+		path = defaultPath
+	}
+
+	return newIngress(domain, Name(domain), domain.Spec.Ingress.Hosts, path, v1beta1.IngressBackend{
+		ServiceName: services.ClusterServiceName(domain),
+		ServicePort: intstr.FromString("http"),
+	})
+}
+
+// NewAdminConsoleForDomain returns an Ingress routing to the admin console of
+// the domain, or nil if the console route is not enabled.
+func NewAdminConsoleForDomain(domain *types.WebLogicDomain) *v1beta1.Ingress {
+	if domain.Spec.Ingress == nil || domain.Spec.Ingress.AdminConsole == nil || !domain.Spec.Ingress.AdminConsole.Enabled {
+		return nil
+	}
+	console := domain.Spec.Ingress.AdminConsole
+
+	hosts := console.Hosts
+	if len(hosts) == 0 {
+		hosts = domain.Spec.Ingress.Hosts
+	}
+	path := console.Path
+	if path == "" {
+		path = defaultAdminConsolePath
+	}
+
+	return newIngress(domain, AdminConsoleName(domain), hosts, path, v1beta1.IngressBackend{
+		ServiceName: services.ServerServiceName(domain, services.AdminServerName),
+		ServicePort: intstr.FromInt(int(domain.AdminPort())),
+	})
+}
@@ -0,0 +1,118 @@
+package networkpolicies
+
+import (
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// Names returns the names of all NetworkPolicies generated for a domain.
+func Names(domain *types.WebLogicDomain) []string {
+	return []string{
+		domain.Name + "-intra-domain",
+		domain.Name + "-ingress-controllers",
+		domain.Name + "-operator",
+	}
+}
+
+func newNetworkPolicy(domain *types.WebLogicDomain, name string, podSelector metav1.LabelSelector, rules ...networkingv1.NetworkPolicyIngressRule) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				domain.Name: "networkpolicy",
+			},
+			Name:      name,
+			Namespace: domain.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: podSelector,
+			Ingress:     rules,
+		},
+	}
+}
+
+func tcpPort(port int32) networkingv1.NetworkPolicyPort {
+	protocol := v1.ProtocolTCP
+	value := intstr.FromInt(int(port))
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &value}
+}
+
+// NewForDomain returns the NetworkPolicies isolating the domain, or none if
+// isolation is not enabled. Pods selected by a policy reject all traffic the
+// policies do not allow.
+func NewForDomain(domain *types.WebLogicDomain) []*networkingv1.NetworkPolicy {
+	settings := domain.Spec.NetworkPolicy
+	if settings == nil || !settings.Enabled {
+		return nil
+	}
+	names := Names(domain)
+
+	domainPods := metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      domain.Name,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"adminserver", "managedserver"},
+		}},
+	}
+	managedServerPods := metav1.LabelSelector{
+		MatchLabels: map[string]string{domain.Name: "managedserver"},
+	}
+	adminServerPods := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			constants.WebLogicDomainLabel: domain.Name,
+			domain.Name:                   "adminserver",
+		},
+	}
+
+	policies := []*networkingv1.NetworkPolicy{
+		newNetworkPolicy(domain, names[0], domainPods, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{{PodSelector: &domainPods}},
+		}),
+	}
+
+	// An empty list of peers would allow traffic from everywhere
+	if len(settings.IngressControllers) > 0 {
+		var ports []networkingv1.NetworkPolicyPort
+		seen := map[int32]bool{}
+		candidates := []int32{domain.ClusterPort(), domain.ClusterSSLPort()}
+		for i := 0; i < domain.Spec.ManagedServerCount; i++ {
+			candidates = append(candidates, domain.ManagedServerPort(i), domain.ManagedServerSSLPort(i))
+		}
+		for _, port := range candidates {
+			if port != 0 && !seen[port] {
+				seen[port] = true
+				ports = append(ports, tcpPort(port))
+			}
+		}
+
+		policies = append(policies, newNetworkPolicy(domain, names[1], managedServerPods, networkingv1.NetworkPolicyIngressRule{
+			Ports: ports,
+			From:  settings.IngressControllers,
+		}))
+	}
+
+	// The operator uses the REST management API over SSL when it is enabled
+	operatorPorts := []networkingv1.NetworkPolicyPort{tcpPort(domain.AdminPort())}
+	if domain.Spec.Ports.AdminSSLPort != 0 {
+		operatorPorts = append(operatorPorts, tcpPort(domain.Spec.Ports.AdminSSLPort))
+	}
+	adminRules := []networkingv1.NetworkPolicyIngressRule{{
+		Ports: operatorPorts,
+		From:  settings.Operator,
+	}}
+
+	// The admin console route of the ingress needs the admin port as well
+	ingress := domain.Spec.Ingress
+	if ingress != nil && ingress.AdminConsole != nil && ingress.AdminConsole.Enabled && len(settings.IngressControllers) > 0 {
+		adminRules = append(adminRules, networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{tcpPort(domain.AdminPort())},
+			From:  settings.IngressControllers,
+		})
+	}
+	policies = append(policies, newNetworkPolicy(domain, names[2], adminServerPods, adminRules...))
+
+	return policies
+}
@@ -0,0 +1,77 @@
+package poddisruptionbudgets
+
+import (
+	"reflect"
+
+	"k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// budgetSpec returns the spec for the given budget, or lets one pod at a time
+// be disrupted if there is none. Counting the unavailable pods keeps the
+// budget right for any replica count, including those chosen by autoscaling.
+func budgetSpec(budget *types.DisruptionBudget, selector map[string]string) v1beta1.PodDisruptionBudgetSpec {
+	spec := v1beta1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: selector},
+	}
+
+	if budget != nil && (budget.MinAvailable != nil || budget.MaxUnavailable != nil) {
+		spec.MinAvailable = budget.MinAvailable
+		spec.MaxUnavailable = budget.MaxUnavailable
+		return spec
+	}
+
+	maxUnavailable := intstr.FromInt(1)
+	spec.MaxUnavailable = &maxUnavailable
+	return spec
+}
+
+// NameForDomain returns the name of the admin server PodDisruptionBudget.
+func NameForDomain(domain *types.WebLogicDomain) string {
+	return domain.Name + "-adminserver"
+}
+
+// NewForDomain creates a new PodDisruptionBudget for the admin server of the given WebLogicDomain.
+func NewForDomain(domain *types.WebLogicDomain) *v1beta1.PodDisruptionBudget {
+	return &v1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: domain.Namespace,
+			Name:      NameForDomain(domain),
+			Labels: map[string]string{
+				constants.WebLogicDomainLabel: domain.Name,
+			},
+		},
+		Spec: budgetSpec(domain.Spec.DisruptionBudget, map[string]string{
+			constants.WebLogicDomainLabel: domain.Name,
+			domain.Name:                   "adminserver",
+		}),
+	}
+}
+
+// NewForServer creates a new PodDisruptionBudget for the given WebLogicManagedServer.
+func NewForServer(server *types.WebLogicManagedServer) *v1beta1.PodDisruptionBudget {
+	labels := map[string]string{
+		constants.WebLogicManagedServerLabel: server.Name,
+		server.Spec.DomainName:               "managedserver",
+	}
+
+	return &v1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: server.Namespace,
+			Name:      server.Name,
+			Labels:    labels,
+		},
+		Spec: budgetSpec(server.Spec.DisruptionBudget, labels),
+	}
+}
+
+// SpecChanged returns true if the desired PodDisruptionBudget differs from the
+// existing one. The spec cannot be updated, so a change means recreating it.
+func SpecChanged(existing *v1beta1.PodDisruptionBudget, desired *v1beta1.PodDisruptionBudget) bool {
+	return !reflect.DeepEqual(existing.Spec.MinAvailable, desired.Spec.MinAvailable) ||
+		!reflect.DeepEqual(existing.Spec.MaxUnavailable, desired.Spec.MaxUnavailable) ||
+		!reflect.DeepEqual(existing.Spec.Selector, desired.Spec.Selector)
+}
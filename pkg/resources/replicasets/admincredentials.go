@@ -0,0 +1,28 @@
+package replicasets
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// addAdminCredentials mounts the Secret holding the credentials of the domain
+// administrator into the first container of the pod template, read by the
+// scripts calling the admin server, see adminCredentials.sh.
+func addAdminCredentials(rs *v1beta1.ReplicaSet, domain *types.WebLogicDomain) {
+	podSpec := &rs.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: domain.Name + "-admin-credentials",
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: domain.AdminSecretName(),
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+		Name:      domain.Name + "-admin-credentials",
+		MountPath: constants.AdminCredentialsMountPath,
+		ReadOnly:  true,
+	})
+}
@@ -5,8 +5,13 @@ import (
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/config"
 	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/configmaps"
+	"weblogic-operator/pkg/resources/services"
 	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/hash"
+	"weblogic-operator/pkg/wlst"
 )
 
 func oracleHomeEnvVar() v1.EnvVar {
@@ -36,15 +41,29 @@ func domainNamespaceEnvVar() v1.EnvVar {
 	}
 }
 
+func dataSourcePasswordEnvVars(model *types.WebLogicDomainModel) []v1.EnvVar {
+	var envVars []v1.EnvVar
+	for _, ds := range model.DataSources {
+		if ds.PasswordSecret == nil {
+			continue
+		}
+		envVars = append(envVars, v1.EnvVar{
+			Name: wlst.DataSourcePasswordEnvVarName(ds),
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: ds.PasswordSecret,
+			},
+		})
+	}
+	return envVars
+}
+
 // Builds the WebLogicDomain container
-func weblogicDomainContainer(domain *types.WebLogicDomain) v1.Container {
-	return v1.Container{
+func weblogicDomainContainer(domain *types.WebLogicDomain, model *types.WebLogicDomainModel) v1.Container {
+	container := v1.Container{
 		Name:            domain.Name + "-adminserver",
-		Image:           fmt.Sprintf("%s:%s", constants.WeblogicImageName, domain.Spec.Version),
+		Image:           config.WebLogicImage(domain.Spec.Version),
 		ImagePullPolicy: v1.PullIfNotPresent,
-		Ports: []v1.ContainerPort{{
-			ContainerPort: 7001},
-		},
+		Ports:           adminContainerPorts(domain),
 		VolumeMounts: []v1.VolumeMount{{
 			Name:      domain.Name + "-storage",
 			MountPath: "/u01/oracle/user_projects"},
@@ -57,20 +76,41 @@ func weblogicDomainContainer(domain *types.WebLogicDomain) v1.Container {
 			managedServerCountEnvVar(domain),
 			domainNamespaceEnvVar(),
 		},
-		Command: []string{"/u01/oracle/user_projects/domainSetup.sh"},
+		Resources:      domain.Spec.Resources,
+		Command:        []string{scriptPath("domainSetup.sh")},
+		ReadinessProbe: readinessProbe(domain.Spec.ReadinessProbe),
+		LivenessProbe:  livenessProbe(domain.Spec.LivenessProbe),
 		Lifecycle: &v1.Lifecycle{
 			PreStop: &v1.Handler{
 				Exec: &v1.ExecAction{
-					Command: []string{"/u01/oracle/user_projects/domains/" + domain.Name + "/bin/stopWebLogic.sh"},
+					Command: []string{scriptPath("shutdownServer.sh"), "AdminServer"},
 				},
 			},
 		},
 	}
+
+	container.Env = append(container.Env, domainPortEnvVars(domain)...)
+	container.Env = append(container.Env, jvmEnvVars(domain.Spec.Resources, domain.Spec.JavaOptions, domain.Spec.UserMemArgs, domain.Spec.HeapPercentage)...)
+
+	if model != nil {
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      domain.Name + "-domain-model",
+			MountPath: constants.DomainModelMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, dataSourcePasswordEnvVars(model)...)
+	}
+
+	return container
 }
 
 // NewForDomain creates a new ReplicationController for the given WebLogicDomain.
-func NewForDomain(domain *types.WebLogicDomain, serviceName string) *v1beta1.ReplicaSet {
-	containers := []v1.Container{weblogicDomainContainer(domain)}
+// When a domain model is given its generated script is mounted into the admin
+// server pod, and the pod template is annotated with the script's hash so a
+// changed model can be detected.
+func NewForDomain(domain *types.WebLogicDomain, serviceName string, model *types.WebLogicDomainModel) *v1beta1.ReplicaSet {
+	containers := []v1.Container{weblogicDomainContainer(domain, model)}
+	replicas := domain.AdminServerReplicas()
 
 	rs := &v1beta1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -81,7 +121,7 @@ func NewForDomain(domain *types.WebLogicDomain, serviceName string) *v1beta1.Rep
 			},
 		},
 		Spec: v1beta1.ReplicaSetSpec{
-			Replicas:        &domain.Spec.Replicas,
+			Replicas:        &replicas,
 			MinReadySeconds: 0,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
@@ -102,14 +142,16 @@ func NewForDomain(domain *types.WebLogicDomain, serviceName string) *v1beta1.Rep
 						Name: domain.Name + "-storage",
 						VolumeSource: v1.VolumeSource{
 							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
-								ClaimName: "weblogic-operator-claim",
+								ClaimName: config.Current().StorageClaimName,
 							},
 						},
 					},
 					},
 					NodeSelector: domain.Spec.NodeSelector,
+					Affinity:     adminServerAffinity(domain.Spec.Affinity, domain.Name),
+					Tolerations:  domain.Spec.Tolerations,
 					ImagePullSecrets: []v1.LocalObjectReference{{
-						Name: "weblogic-docker-store",
+						Name: config.Current().ImagePullSecret,
 					},
 					},
 					Containers: containers,
@@ -118,5 +160,30 @@ func NewForDomain(domain *types.WebLogicDomain, serviceName string) *v1beta1.Rep
 		},
 	}
 
+	if model != nil {
+		podSpec := &rs.Spec.Template.Spec
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: domain.Name + "-domain-model",
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: configmaps.DomainModelConfigMapName(domain),
+					},
+				},
+			},
+		})
+		setTemplateAnnotation(rs, constants.DomainModelHashAnnotation, hash.ForString(wlst.NewDomainModelScript(domain, model)))
+	}
+
+	addConfigOverrides(rs, domain)
+	addTLS(rs, domain, services.AdminServerName)
+	addAdminCredentials(rs, domain)
+	addScripts(rs, domain)
+	addShutdown(rs, domain.Spec.Shutdown)
+	addNodeManager(rs, domain)
+	addLogExport(rs, domain)
+	addServerPod(rs, &domain.Spec.ServerPod)
+	setPodTemplateHash(rs)
+
 	return rs
 }
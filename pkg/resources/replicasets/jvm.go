@@ -0,0 +1,41 @@
+package replicasets
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+)
+
+const defaultHeapPercentage = 75
+
+// userMemArgs returns the JVM memory arguments for a server container. Explicit
+// arguments win, otherwise the heap is sized as a percentage of the memory limit.
+func userMemArgs(resources v1.ResourceRequirements, memArgs string, heapPercentage int32) string {
+	if memArgs != "" {
+		return memArgs
+	}
+
+	limit, ok := resources.Limits[v1.ResourceMemory]
+	if !ok || limit.IsZero() {
+		return ""
+	}
+
+	if heapPercentage <= 0 || heapPercentage > 100 {
+		heapPercentage = defaultHeapPercentage
+	}
+	heapMB := limit.Value() / (1024 * 1024) * int64(heapPercentage) / 100
+	return fmt.Sprintf("-Xms%dm -Xmx%dm", heapMB, heapMB)
+}
+
+// jvmEnvVars builds the variables startWebLogic.sh and startManagedWebLogic.sh
+// read the JVM arguments from.
+func jvmEnvVars(resources v1.ResourceRequirements, javaOptions string, memArgs string, heapPercentage int32) []v1.EnvVar {
+	var envVars []v1.EnvVar
+	if javaOptions != "" {
+		envVars = append(envVars, v1.EnvVar{Name: "JAVA_OPTIONS", Value: javaOptions})
+	}
+	if args := userMemArgs(resources, memArgs, heapPercentage); args != "" {
+		envVars = append(envVars, v1.EnvVar{Name: "USER_MEM_ARGS", Value: args})
+	}
+	return envVars
+}
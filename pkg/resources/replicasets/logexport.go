@@ -0,0 +1,88 @@
+package replicasets
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/config"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/hash"
+)
+
+// logExporterImage returns the image of the log exporter sidecar of a domain.
+func logExporterImage(domain *types.WebLogicDomain) string {
+	if domain.Spec.LogExport.Image != "" {
+		return domain.Spec.LogExport.Image
+	}
+	return config.Current().LogExporterImage
+}
+
+// LogExportHash returns a digest of the log export settings of a domain, or
+// an empty string if its logs are not exported.
+func LogExportHash(domain *types.WebLogicDomain) string {
+	if !domain.LogExportEnabled() {
+		return ""
+	}
+	content, err := json.Marshal(domain.Spec.LogExport)
+	if err != nil {
+		glog.Errorf("Unable to hash the log export of %s: %s", domain.Name, err)
+		return ""
+	}
+	return hash.ForString(string(content) + logExporterImage(domain))
+}
+
+// addLogExport shares an emptyDir between the first container of the pod
+// template, whose scripts then write the server logs into it, and a sidecar
+// printing them to stdout, if the domain exports its logs. It must be called
+// after addShutdown so the sidecar follows the logs of a stopping server for
+// the grace period of the pod.
+func addLogExport(rs *v1beta1.ReplicaSet, domain *types.WebLogicDomain) {
+	if !domain.LogExportEnabled() {
+		return
+	}
+
+	podSpec := &rs.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: domain.Name + "-logs",
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{},
+		},
+	})
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+		Name:      domain.Name + "-logs",
+		MountPath: constants.LogExportMountPath,
+	})
+	container.Env = append(container.Env, v1.EnvVar{Name: "LOG_EXPORT_DIR", Value: constants.LogExportMountPath})
+
+	// Leave time to print the last records before the pod is killed
+	gracePeriod := int64(constants.LogExportDrainSeconds)
+	if podSpec.TerminationGracePeriodSeconds != nil && *podSpec.TerminationGracePeriodSeconds > 2*gracePeriod {
+		gracePeriod = *podSpec.TerminationGracePeriodSeconds - gracePeriod
+	}
+
+	podSpec.Containers = append(podSpec.Containers, v1.Container{
+		Name:  constants.LogExporterContainer,
+		Image: logExporterImage(domain),
+		Command: []string{
+			constants.LogExporterCommand,
+			"--dir=" + constants.LogExportMountPath,
+			"--domain=" + domain.Name,
+			fmt.Sprintf("--grace-period=%ds", gracePeriod),
+		},
+		Resources: domain.Spec.LogExport.Resources,
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      domain.Name + "-logs",
+				MountPath: constants.LogExportMountPath,
+				ReadOnly:  true,
+			},
+		},
+	})
+	setTemplateAnnotation(rs, constants.LogExportHashAnnotation, LogExportHash(domain))
+}
@@ -0,0 +1,23 @@
+package replicasets
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/types"
+)
+
+// addNodeManager tells the scripts of the pods to configure and start the
+// servers through a Node Manager, if the domain enables it.
+func addNodeManager(rs *v1beta1.ReplicaSet, domain *types.WebLogicDomain) {
+	if !domain.NodeManagerEnabled() {
+		return
+	}
+
+	container := &rs.Spec.Template.Spec.Containers[0]
+	container.Env = append(container.Env,
+		v1.EnvVar{Name: "NODE_MANAGER_ENABLED", Value: "true"},
+		v1.EnvVar{Name: "NODE_MANAGER_PORT", Value: fmt.Sprint(domain.NodeManagerPort())},
+	)
+}
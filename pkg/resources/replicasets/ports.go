@@ -0,0 +1,88 @@
+package replicasets
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	"weblogic-operator/pkg/types"
+)
+
+func adminPortEnvVar(domain *types.WebLogicDomain) v1.EnvVar {
+	return v1.EnvVar{Name: "ADMIN_PORT", Value: fmt.Sprint(domain.AdminPort())}
+}
+
+// t3ChannelsValue encodes the T3 channels for the domain creation script as
+// comma separated name:port:publicAddress:publicPort entries.
+func t3ChannelsValue(channels []types.T3Channel) string {
+	entries := make([]string, len(channels))
+	for i, channel := range channels {
+		publicPort := channel.PublicPort
+		if publicPort == 0 {
+			publicPort = channel.Port
+		}
+		entries[i] = fmt.Sprintf("%s:%d:%s:%d", channel.Name, channel.Port, channel.PublicAddress, publicPort)
+	}
+	return strings.Join(entries, ",")
+}
+
+// domainPortEnvVars passes the ports of the domain to the domain creation script.
+func domainPortEnvVars(domain *types.WebLogicDomain) []v1.EnvVar {
+	return []v1.EnvVar{
+		adminPortEnvVar(domain),
+		{Name: "ADMIN_SSL_PORT", Value: fmt.Sprint(domain.Spec.Ports.AdminSSLPort)},
+		{Name: "MANAGED_SERVER_BASE_PORT", Value: fmt.Sprint(domain.ManagedServerBasePort())},
+		{Name: "MANAGED_SERVER_PORT_STEP", Value: fmt.Sprint(domain.ManagedServerPortStep())},
+		{Name: "MANAGED_SERVER_SSL_BASE_PORT", Value: fmt.Sprint(domain.Spec.Ports.ManagedServerSSLBasePort)},
+		{Name: "T3_CHANNELS", Value: t3ChannelsValue(domain.Spec.Ports.T3Channels)},
+		{Name: "CLUSTER_PORT", Value: fmt.Sprint(clusterChannelPort(domain, domain.ClusterPort()))},
+		{Name: "CLUSTER_SSL_PORT", Value: fmt.Sprint(clusterChannelPort(domain, domain.ClusterSSLPort()))},
+	}
+}
+
+// clusterChannelPort returns the port of a channel to add to the managed
+// servers for the cluster Service, or 0 if they share their own ports.
+func clusterChannelPort(domain *types.WebLogicDomain, port int32) int32 {
+	if !domain.ClusterChannelsEnabled() {
+		return 0
+	}
+	return port
+}
+
+func adminContainerPorts(domain *types.WebLogicDomain) []v1.ContainerPort {
+	ports := []v1.ContainerPort{{
+		Name:          "admin",
+		ContainerPort: domain.AdminPort(),
+	}}
+	if domain.Spec.Ports.AdminSSLPort != 0 {
+		ports = append(ports, v1.ContainerPort{
+			Name:          "admin-ssl",
+			ContainerPort: domain.Spec.Ports.AdminSSLPort,
+		})
+	}
+	for _, channel := range domain.Spec.Ports.T3Channels {
+		ports = append(ports, v1.ContainerPort{
+			Name:          channel.Name,
+			ContainerPort: channel.Port,
+		})
+	}
+	return ports
+}
+
+// managedContainerPorts returns the ports every managed server pod listens
+// on. A pod only learns the server it runs when it starts, so only the ports
+// shared by all managed servers can be declared. The cluster Service selects
+// them by name.
+func managedContainerPorts(domain *types.WebLogicDomain) []v1.ContainerPort {
+	ports := []v1.ContainerPort{{
+		Name:          "http",
+		ContainerPort: domain.ClusterPort(),
+	}}
+	if sslPort := domain.ClusterSSLPort(); sslPort != 0 {
+		ports = append(ports, v1.ContainerPort{
+			Name:          "https",
+			ContainerPort: sslPort,
+		})
+	}
+	return ports
+}
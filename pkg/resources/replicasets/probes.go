@@ -0,0 +1,63 @@
+package replicasets
+
+import (
+	"k8s.io/api/core/v1"
+	"weblogic-operator/pkg/types"
+)
+
+// Defaults for the generated probes. The liveness probe is lenient since it
+// also covers the time a server needs to boot.
+var (
+	defaultReadinessProbe = types.ProbeSettings{
+		InitialDelaySeconds: 30,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       10,
+		FailureThreshold:    3,
+	}
+	defaultLivenessProbe = types.ProbeSettings{
+		InitialDelaySeconds: 60,
+		TimeoutSeconds:      10,
+		PeriodSeconds:       30,
+		FailureThreshold:    3,
+	}
+)
+
+func newProbe(command string, settings *types.ProbeSettings, defaults types.ProbeSettings) *v1.Probe {
+	probe := &v1.Probe{
+		Handler: v1.Handler{
+			Exec: &v1.ExecAction{
+				Command: []string{command},
+			},
+		},
+		InitialDelaySeconds: defaults.InitialDelaySeconds,
+		TimeoutSeconds:      defaults.TimeoutSeconds,
+		PeriodSeconds:       defaults.PeriodSeconds,
+		FailureThreshold:    defaults.FailureThreshold,
+	}
+
+	if settings != nil {
+		if settings.InitialDelaySeconds > 0 {
+			probe.InitialDelaySeconds = settings.InitialDelaySeconds
+		}
+		if settings.TimeoutSeconds > 0 {
+			probe.TimeoutSeconds = settings.TimeoutSeconds
+		}
+		if settings.PeriodSeconds > 0 {
+			probe.PeriodSeconds = settings.PeriodSeconds
+		}
+		if settings.FailureThreshold > 0 {
+			probe.FailureThreshold = settings.FailureThreshold
+		}
+	}
+	return probe
+}
+
+// readinessProbe reports a pod ready once its WebLogic server is RUNNING and healthy.
+func readinessProbe(settings *types.ProbeSettings) *v1.Probe {
+	return newProbe(scriptPath("readinessProbe.sh"), settings, defaultReadinessProbe)
+}
+
+// livenessProbe fails once the WebLogic server of a pod has died or entered FAILED.
+func livenessProbe(settings *types.ProbeSettings) *v1.Probe {
+	return newProbe(scriptPath("livenessProbe.sh"), settings, defaultLivenessProbe)
+}
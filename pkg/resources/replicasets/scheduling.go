@@ -0,0 +1,64 @@
+package replicasets
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/constants"
+)
+
+const hostnameTopologyKey = "kubernetes.io/hostname"
+
+func adminServerLabels(domainName string) map[string]string {
+	return map[string]string{
+		constants.WebLogicDomainLabel: domainName,
+		domainName:                    "adminserver",
+	}
+}
+
+func managedServerLabels(domainName string) map[string]string {
+	return map[string]string{
+		domainName: "managedserver",
+	}
+}
+
+func preferOtherNodes(weight int32, labels map[string]string) v1.WeightedPodAffinityTerm {
+	return v1.WeightedPodAffinityTerm{
+		Weight: weight,
+		PodAffinityTerm: v1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+			TopologyKey:   hostnameTopologyKey,
+		},
+	}
+}
+
+// adminServerAffinity returns the given affinity or, if unset, one that
+// prefers nodes not running managed servers of the domain.
+func adminServerAffinity(affinity *v1.Affinity, domainName string) *v1.Affinity {
+	if affinity != nil {
+		return affinity
+	}
+	return &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+				preferOtherNodes(50, managedServerLabels(domainName)),
+			},
+		},
+	}
+}
+
+// managedServerAffinity returns the given affinity or, if unset, one that
+// spreads the managed servers of a domain across nodes and keeps them off the
+// admin server's node when possible.
+func managedServerAffinity(affinity *v1.Affinity, domainName string) *v1.Affinity {
+	if affinity != nil {
+		return affinity
+	}
+	return &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+				preferOtherNodes(100, managedServerLabels(domainName)),
+				preferOtherNodes(50, adminServerLabels(domainName)),
+			},
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package replicasets
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/hash"
+)
+
+// addServerPod merges the additions of the user into the pod template. It must
+// be called last so anything the operator set is known and kept: additions
+// reusing an operator name, path or key are skipped with a warning.
+func addServerPod(rs *v1beta1.ReplicaSet, serverPod *types.ServerPod) {
+	template := &rs.Spec.Template
+	podSpec := &template.Spec
+	container := &podSpec.Containers[0]
+
+	volumes := map[string]bool{}
+	for _, volume := range podSpec.Volumes {
+		volumes[volume.Name] = true
+	}
+	for _, volume := range serverPod.Volumes {
+		if volumes[volume.Name] {
+			glog.Warningf("Ignoring volume %s of %s, the name is already in use", volume.Name, rs.Name)
+			continue
+		}
+		volumes[volume.Name] = true
+		podSpec.Volumes = append(podSpec.Volumes, volume)
+	}
+
+	mounts := map[string]bool{}
+	for _, mount := range container.VolumeMounts {
+		mounts[mount.Name] = true
+		mounts[mount.MountPath] = true
+	}
+	for _, mount := range serverPod.VolumeMounts {
+		if mounts[mount.Name] || mounts[mount.MountPath] {
+			glog.Warningf("Ignoring volume mount %s at %s of %s, the volume or path is already mounted", mount.Name, mount.MountPath, rs.Name)
+			continue
+		}
+		mounts[mount.Name] = true
+		mounts[mount.MountPath] = true
+		container.VolumeMounts = append(container.VolumeMounts, mount)
+	}
+
+	env := map[string]bool{}
+	for _, variable := range container.Env {
+		env[variable.Name] = true
+	}
+	for _, variable := range serverPod.Env {
+		if env[variable.Name] {
+			glog.Warningf("Ignoring environment variable %s of %s, it is set by the operator", variable.Name, rs.Name)
+			continue
+		}
+		env[variable.Name] = true
+		container.Env = append(container.Env, variable)
+	}
+	// Variables in env take precedence over those of envFrom
+	container.EnvFrom = append(container.EnvFrom, serverPod.EnvFrom...)
+
+	containers := map[string]bool{}
+	for _, c := range podSpec.Containers {
+		containers[c.Name] = true
+	}
+	for _, c := range podSpec.InitContainers {
+		containers[c.Name] = true
+	}
+	for _, sidecar := range serverPod.Sidecars {
+		if containers[sidecar.Name] {
+			glog.Warningf("Ignoring sidecar %s of %s, the name is already in use", sidecar.Name, rs.Name)
+			continue
+		}
+		containers[sidecar.Name] = true
+		podSpec.Containers = append(podSpec.Containers, sidecar)
+	}
+	for _, initContainer := range serverPod.InitContainers {
+		if containers[initContainer.Name] {
+			glog.Warningf("Ignoring init container %s of %s, the name is already in use", initContainer.Name, rs.Name)
+			continue
+		}
+		containers[initContainer.Name] = true
+		podSpec.InitContainers = append(podSpec.InitContainers, initContainer)
+	}
+
+	for key, value := range serverPod.PodLabels {
+		if _, found := template.Labels[key]; found || key == constants.WebLogicServerNameLabel {
+			glog.Warningf("Ignoring pod label %s of %s, it is set by the operator", key, rs.Name)
+			continue
+		}
+		if template.Labels == nil {
+			template.Labels = map[string]string{}
+		}
+		template.Labels[key] = value
+	}
+
+	for key, value := range serverPod.PodAnnotations {
+		if _, found := template.Annotations[key]; found || strings.HasPrefix(key, constants.OperatorAnnotationPrefix) {
+			glog.Warningf("Ignoring pod annotation %s of %s, it is set by the operator", key, rs.Name)
+			continue
+		}
+		setTemplateAnnotation(rs, key, value)
+	}
+
+	// Restart the pods when the additions change, leaving pods without any
+	// as they were
+	content, err := json.Marshal(serverPod)
+	if err != nil {
+		glog.Errorf("Unable to hash the server pod of %s: %s", rs.Name, err)
+		return
+	}
+	if string(content) == "{}" {
+		return
+	}
+	setTemplateAnnotation(rs, constants.ServerPodHashAnnotation, hash.ForString(string(content)))
+}
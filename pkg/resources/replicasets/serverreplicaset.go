@@ -1,11 +1,12 @@
 package replicasets
 
 import (
-	"fmt"
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/config"
 	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/secrets"
 	"weblogic-operator/pkg/types"
 )
 
@@ -33,13 +34,11 @@ func podNameEnvVar() v1.EnvVar {
 
 // Builds the WebLogicManagedServer container
 func WebLogicManagedServerContainer(server *types.WebLogicManagedServer) v1.Container {
-	return v1.Container{
+	container := v1.Container{
 		Name:            server.Spec.DomainName + "-managedserver",
-		Image:           fmt.Sprintf("%s:%s", constants.WeblogicImageName, server.Spec.Domain.Spec.Version),
+		Image:           config.WebLogicImage(server.Spec.Domain.Spec.Version),
 		ImagePullPolicy: v1.PullIfNotPresent,
-		//Ports: []v1.ContainerPort{{
-		//	ContainerPort: 7001},
-		//},
+		Ports:           managedContainerPorts(&server.Spec.Domain),
 		VolumeMounts: []v1.VolumeMount{{
 			Name:      server.Spec.DomainName + "-storage",
 			MountPath: "/u01/oracle/user_projects"},
@@ -51,20 +50,29 @@ func WebLogicManagedServerContainer(server *types.WebLogicManagedServer) v1.Cont
 			domainHomeEnvVar(&server.Spec.Domain),
 			serverNamespaceEnvVar(),
 		},
-		Command: []string{"/u01/oracle/user_projects/startServer.sh"},
+		Resources:      server.Spec.Resources,
+		Command:        []string{scriptPath("startServer.sh")},
+		ReadinessProbe: readinessProbe(server.Spec.ReadinessProbe),
+		LivenessProbe:  livenessProbe(server.Spec.LivenessProbe),
 		Lifecycle: &v1.Lifecycle{
 			PreStop: &v1.Handler{
 				Exec: &v1.ExecAction{
-					Command: []string{"/u01/oracle/user_projects/stopServer.sh"},
+					Command: []string{scriptPath("stopServer.sh")},
 				},
 			},
 		},
 	}
+
+	container.Env = append(container.Env, adminPortEnvVar(&server.Spec.Domain))
+	container.Env = append(container.Env, jvmEnvVars(server.Spec.Resources, server.Spec.JavaOptions, server.Spec.UserMemArgs, server.Spec.HeapPercentage)...)
+
+	return container
 }
 
 // NewForServer creates a new ReplicationController for the given WebLogicManagedServer.
 func NewForServer(server *types.WebLogicManagedServer, serviceName string) *v1beta1.ReplicaSet {
 	containers := []v1.Container{WebLogicManagedServerContainer(server)}
+	replicas := server.ReplicaSetReplicas(nil)
 
 	rs := &v1beta1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -76,7 +84,7 @@ func NewForServer(server *types.WebLogicManagedServer, serviceName string) *v1be
 			},
 		},
 		Spec: v1beta1.ReplicaSetSpec{
-			Replicas:        &server.Spec.ServersToRun,
+			Replicas:        &replicas,
 			MinReadySeconds: 0,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
@@ -98,16 +106,18 @@ func NewForServer(server *types.WebLogicManagedServer, serviceName string) *v1be
 							Name: server.Spec.DomainName + "-storage",
 							VolumeSource: v1.VolumeSource{
 								PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
-									ClaimName: "weblogic-operator-claim",
+									ClaimName: config.Current().StorageClaimName,
 								},
 							},
 						},
 					},
 					//TODO: refer to same selector of this.replicaset spec
 					NodeSelector: server.Spec.NodeSelector,
+					Affinity:     managedServerAffinity(server.Spec.Affinity, server.Spec.DomainName),
+					Tolerations:  server.Spec.Tolerations,
 					ImagePullSecrets: []v1.LocalObjectReference{
 						{
-							Name: "weblogic-docker-store",
+							Name: config.Current().ImagePullSecret,
 						},
 					},
 					Containers: containers,
@@ -116,5 +126,15 @@ func NewForServer(server *types.WebLogicManagedServer, serviceName string) *v1be
 		},
 	}
 
+	addConfigOverrides(rs, &server.Spec.Domain)
+	addTLS(rs, &server.Spec.Domain, secrets.ManagedServerIdentity)
+	addAdminCredentials(rs, &server.Spec.Domain)
+	addScripts(rs, &server.Spec.Domain)
+	addShutdown(rs, server.Spec.Shutdown)
+	addNodeManager(rs, &server.Spec.Domain)
+	addLogExport(rs, &server.Spec.Domain)
+	addServerPod(rs, &server.Spec.ServerPod)
+	setPodTemplateHash(rs)
+
 	return rs
 }
@@ -0,0 +1,29 @@
+package replicasets
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/types"
+)
+
+// addShutdown gives the pods time to shut their server down as configured and
+// tells the stop scripts how to shut it down.
+func addShutdown(rs *v1beta1.ReplicaSet, shutdown *types.ShutdownOptions) {
+	podSpec := &rs.Spec.Template.Spec
+	gracePeriod := shutdown.TerminationGracePeriodSeconds()
+	podSpec.TerminationGracePeriodSeconds = &gracePeriod
+
+	shutdownType := types.ShutdownGraceful
+	if shutdown.Forced() {
+		shutdownType = types.ShutdownForced
+	}
+
+	container := &podSpec.Containers[0]
+	container.Env = append(container.Env,
+		v1.EnvVar{Name: "SHUTDOWN_TYPE", Value: string(shutdownType)},
+		v1.EnvVar{Name: "SHUTDOWN_TIMEOUT_SECONDS", Value: fmt.Sprint(shutdown.Timeout())},
+		v1.EnvVar{Name: "SHUTDOWN_IGNORE_SESSIONS", Value: fmt.Sprint(shutdown.SessionsIgnored())},
+	)
+}
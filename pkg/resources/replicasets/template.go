@@ -0,0 +1,96 @@
+package replicasets
+
+import (
+	"encoding/json"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/configmaps"
+	"weblogic-operator/pkg/scripts"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/hash"
+)
+
+// scriptPath returns the path of a script in the server pods.
+func scriptPath(name string) string {
+	return constants.ScriptsMountPath + "/" + name
+}
+
+// TemplateChanged returns true if the desired ReplicaSet starts its pods from
+// a template that differs from the existing one.
+func TemplateChanged(existing *v1beta1.ReplicaSet, desired *v1beta1.ReplicaSet) bool {
+	return existing.Spec.Template.Annotations[constants.PodTemplateHashAnnotation] !=
+		desired.Spec.Template.Annotations[constants.PodTemplateHashAnnotation]
+}
+
+// setPodTemplateHash records a digest of the whole pod template, as the API
+// server fills in defaults that keep the templates from being compared
+// directly. It must be called once the template is complete.
+func setPodTemplateHash(rs *v1beta1.ReplicaSet) {
+	content, err := json.Marshal(rs.Spec.Template)
+	if err != nil {
+		glog.Errorf("Unable to hash the pod template of %s: %s", rs.Name, err)
+		return
+	}
+	setTemplateAnnotation(rs, constants.PodTemplateHashAnnotation, hash.ForString(string(content)))
+}
+
+func setTemplateAnnotation(rs *v1beta1.ReplicaSet, key, value string) {
+	if rs.Spec.Template.Annotations == nil {
+		rs.Spec.Template.Annotations = map[string]string{}
+	}
+	rs.Spec.Template.Annotations[key] = value
+}
+
+// addConfigOverrides mounts the config overrides of the domain into the first
+// container of the pod template.
+func addConfigOverrides(rs *v1beta1.ReplicaSet, domain *types.WebLogicDomain) {
+	if domain.Spec.ConfigOverrides == "" {
+		return
+	}
+
+	podSpec := &rs.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: domain.Name + "-config-overrides",
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: domain.Spec.ConfigOverrides,
+				},
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+		Name:      domain.Name + "-config-overrides",
+		MountPath: constants.ConfigOverridesMountPath,
+		ReadOnly:  true,
+	})
+	setTemplateAnnotation(rs, constants.ConfigOverridesHashAnnotation, domain.Status.ConfigOverridesHash)
+}
+
+// addScripts mounts the scripts ConfigMap of the domain into the first
+// container of the pod template, executable and read-only, and records their
+// version so pods are restarted when the scripts change.
+func addScripts(rs *v1beta1.ReplicaSet, domain *types.WebLogicDomain) {
+	mode := int32(0755)
+	podSpec := &rs.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: domain.Name + "-scripts",
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: configmaps.ScriptsConfigMapName(domain, scripts.Hash()),
+				},
+				DefaultMode: &mode,
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+		Name:      domain.Name + "-scripts",
+		MountPath: constants.ScriptsMountPath,
+		ReadOnly:  true,
+	})
+	setTemplateAnnotation(rs, constants.ScriptsHashAnnotation, scripts.Hash())
+}
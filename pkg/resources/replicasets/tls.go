@@ -0,0 +1,74 @@
+package replicasets
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/secrets"
+	"weblogic-operator/pkg/types"
+)
+
+// tlsItems projects the CA certificate and the given identity of a generated
+// TLS Secret as ca.crt, tls.crt and tls.key, leaving out the CA key and the
+// other identities. A Secret of the domain holds these keys already.
+func tlsItems(domain *types.WebLogicDomain, identity string) []v1.KeyToPath {
+	certificate, privateKey := constants.TLSCertificateKey, constants.TLSPrivateKeyKey
+	if domain.Spec.TLS.Secret == "" {
+		certificate, privateKey = secrets.CertificateKey(identity), secrets.PrivateKeyKey(identity)
+	}
+	return []v1.KeyToPath{
+		{Key: constants.TLSCACertificateKey, Path: constants.TLSCACertificateKey},
+		{Key: certificate, Path: constants.TLSCertificateKey},
+		{Key: privateKey, Path: constants.TLSPrivateKeyKey},
+	}
+}
+
+// addTLS mounts the CA certificate and the given identity of the domain into
+// the first container of the pod template and tells the scripts to build
+// keystores and use t3s. The keystore password is passed in the environment.
+func addTLS(rs *v1beta1.ReplicaSet, domain *types.WebLogicDomain, identity string) {
+	if domain.Spec.TLS == nil {
+		return
+	}
+
+	optional := true
+	podSpec := &rs.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: domain.Name + "-tls",
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: domain.TLSSecretName(),
+				Items:      tlsItems(domain, identity),
+			},
+		},
+	})
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+		Name:      domain.Name + "-tls",
+		MountPath: constants.TLSMountPath,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env,
+		v1.EnvVar{Name: "TLS_ENABLED", Value: "true"},
+		v1.EnvVar{
+			Name: "KEYSTORE_PASSWORD",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: domain.TLSSecretName()},
+					Key:                  constants.TLSKeystorePasswordKey,
+					Optional:             &optional,
+				},
+			},
+		},
+	)
+
+	for _, env := range container.Env {
+		if env.Name == "ADMIN_SSL_PORT" {
+			return
+		}
+	}
+	container.Env = append(container.Env, v1.EnvVar{Name: "ADMIN_SSL_PORT", Value: fmt.Sprint(domain.Spec.Ports.AdminSSLPort)})
+}
@@ -0,0 +1,27 @@
+package secrets
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// NewAdminCredentialsSecret returns a Secret for the generated credentials of
+// the administrator of the domain.
+func NewAdminCredentialsSecret(domain *types.WebLogicDomain, username, password string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				domain.Name: "admin-credentials",
+			},
+			Name:      domain.AdminSecretName(),
+			Namespace: domain.Namespace,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			constants.AdminUsernameKey: []byte(username),
+			constants.AdminPasswordKey: []byte(password),
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package secrets
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/types"
+)
+
+// ManagedServerIdentity names the certificate shared by the managed servers
+// in a generated TLS Secret. A pod only learns the server it runs when it
+// starts, so all managed server pods mount the same identity.
+const ManagedServerIdentity = "managedserver"
+
+// CertificateKey returns the key of the certificate of an identity in a generated TLS Secret.
+func CertificateKey(identity string) string {
+	return identity + ".crt"
+}
+
+// PrivateKeyKey returns the key of the private key of an identity in a generated TLS Secret.
+func PrivateKeyKey(identity string) string {
+	return identity + ".key"
+}
+
+// NewTLSSecret returns a Secret for the generated certificates of the domain.
+func NewTLSSecret(domain *types.WebLogicDomain, data map[string][]byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				domain.Name: "tls",
+			},
+			Name:      domain.TLSSecretName(),
+			Namespace: domain.Namespace,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: data,
+	}
+}
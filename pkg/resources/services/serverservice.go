@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// tolerateUnreadyEndpointsAnnotation keeps booting servers addressable, which
+// the admin server and T3 clients need before a server reports ready.
+const tolerateUnreadyEndpointsAnnotation = "service.alpha.kubernetes.io/tolerate-unready-endpoints"
+
+// AdminServerName is the name of the admin server in every domain.
+const AdminServerName = "AdminServer"
+
+func serviceType(serviceType v1.ServiceType) v1.ServiceType {
+	if serviceType == "" {
+		return v1.ServiceTypeClusterIP
+	}
+	return serviceType
+}
+
+// ServerServiceName returns the name of the Service of a WebLogic server.
+func ServerServiceName(domain *types.WebLogicDomain, serverName string) string {
+	if serverName == AdminServerName {
+		return domain.Name + "-adminserver"
+	}
+	return fmt.Sprintf("%s-%s", domain.Name, serverName)
+}
+
+// ClusterServiceName returns the name of the Service balancing across the
+// managed servers of a domain.
+func ClusterServiceName(domain *types.WebLogicDomain) string {
+	return domain.Name + "-cluster"
+}
+
+func newServerService(domain *types.WebLogicDomain, serverName string, ports []v1.ServicePort, selector map[string]string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				constants.WebLogicServerNameLabel: serverName,
+				domain.Name:                       "serverservice",
+			},
+			Annotations: map[string]string{
+				tolerateUnreadyEndpointsAnnotation: "true",
+			},
+			Name:      ServerServiceName(domain, serverName),
+			Namespace: domain.Namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Type:     serviceType(domain.Spec.Services.ServerServiceType),
+			Ports:    ports,
+			Selector: selector,
+		},
+	}
+}
+
+// NewServerServicesForDomain returns a Service for each WebLogic server of the
+// domain. Managed server pods are selected by the server name they run.
+func NewServerServicesForDomain(domain *types.WebLogicDomain) []*v1.Service {
+	svcs := []*v1.Service{
+		newServerService(domain, AdminServerName, adminServicePorts(domain), map[string]string{
+			constants.WebLogicDomainLabel: domain.Name,
+			domain.Name:                   "adminserver",
+		}),
+	}
+
+	for i, serverName := range domain.ManagedServerNames() {
+		ports := []v1.ServicePort{{
+			Name:     "default",
+			Port:     domain.ManagedServerPort(i),
+			Protocol: v1.ProtocolTCP,
+		}}
+		if sslPort := domain.ManagedServerSSLPort(i); sslPort != 0 {
+			ports = append(ports, v1.ServicePort{
+				Name:     "ssl",
+				Port:     sslPort,
+				Protocol: v1.ProtocolTCP,
+			})
+		}
+		// The admin server reaches the Node Manager of a server through its Service
+		if port := domain.NodeManagerPort(); port != 0 {
+			ports = append(ports, v1.ServicePort{
+				Name:     "nodemanager",
+				Port:     port,
+				Protocol: v1.ProtocolTCP,
+			})
+		}
+
+		svcs = append(svcs, newServerService(domain, serverName, ports, map[string]string{
+			constants.WebLogicServerNameLabel: serverName,
+			domain.Name:                       "managedserver",
+		}))
+	}
+	return svcs
+}
+
+// NewClusterServiceForDomain returns a Service balancing HTTP traffic across
+// the ready managed servers of the domain, on the ports they all listen on.
+func NewClusterServiceForDomain(domain *types.WebLogicDomain) *v1.Service {
+	ports := []v1.ServicePort{{
+		Name:       "http",
+		Port:       domain.ClusterPort(),
+		TargetPort: intstr.FromString("http"),
+		Protocol:   v1.ProtocolTCP,
+	}}
+	if sslPort := domain.ClusterSSLPort(); sslPort != 0 {
+		ports = append(ports, v1.ServicePort{
+			Name:       "https",
+			Port:       sslPort,
+			TargetPort: intstr.FromString("https"),
+			Protocol:   v1.ProtocolTCP,
+		})
+	}
+
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				domain.Name: "clusterservice",
+			},
+			Name:      ClusterServiceName(domain),
+			Namespace: domain.Namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Type:  serviceType(domain.Spec.Services.ClusterServiceType),
+			Ports: ports,
+			Selector: map[string]string{
+				domain.Name: "managedserver",
+			},
+		},
+	}
+}
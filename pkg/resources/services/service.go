@@ -11,16 +11,28 @@ import (
 
 // NewServiceForServer will return a new NodePort Kubernetes service for a WeblogicManagedServer
 func NewServiceForServer(server *types.WebLogicManagedServer) *v1.Service {
-	var startPort int32 = 7001
+	domain := &server.Spec.Domain
 	//var weblogicPorts []v1.ServicePort
-	weblogicPorts := make([]v1.ServicePort, server.Spec.Domain.Spec.ManagedServerCount)
+	weblogicPorts := make([]v1.ServicePort, 0, domain.Spec.ManagedServerCount)
 
-	for i := 1; i <= server.Spec.Domain.Spec.ManagedServerCount; i++ {
-		var port = startPort + int32(i*2)
+	// Managed servers share their ports when the port step is 0
+	seen := map[int32]bool{}
+	for i := 0; i < domain.Spec.ManagedServerCount; i++ {
+		var port = domain.ManagedServerPort(i)
 		glog.V(4).Info("Calculated port ", fmt.Sprint(port))
-		weblogicPorts[i-1] = v1.ServicePort{
-			Name: fmt.Sprint("managedserver-", i-1, "port"),
-			Port: port,
+		if !seen[port] {
+			seen[port] = true
+			weblogicPorts = append(weblogicPorts, v1.ServicePort{
+				Name: fmt.Sprint("managedserver-", i, "port"),
+				Port: port,
+			})
+		}
+		if sslPort := domain.ManagedServerSSLPort(i); sslPort != 0 && !seen[sslPort] {
+			seen[sslPort] = true
+			weblogicPorts = append(weblogicPorts, v1.ServicePort{
+				Name: fmt.Sprint("managedserver-", i, "ssl"),
+				Port: sslPort,
+			})
 		}
 	}
 	svc := &v1.Service{
@@ -44,8 +56,32 @@ func NewServiceForServer(server *types.WebLogicManagedServer) *v1.Service {
 	return svc
 }
 
+// adminServicePorts returns the ports of the admin server: its listen port,
+// SSL port and T3 channels.
+func adminServicePorts(domain *types.WebLogicDomain) []v1.ServicePort {
+	ports := []v1.ServicePort{{
+		Name:     "admin",
+		Port:     domain.AdminPort(),
+		Protocol: v1.ProtocolTCP,
+	}}
+	if domain.Spec.Ports.AdminSSLPort != 0 {
+		ports = append(ports, v1.ServicePort{
+			Name:     "admin-ssl",
+			Port:     domain.Spec.Ports.AdminSSLPort,
+			Protocol: v1.ProtocolTCP,
+		})
+	}
+	for _, channel := range domain.Spec.Ports.T3Channels {
+		ports = append(ports, v1.ServicePort{
+			Name:     channel.Name,
+			Port:     channel.Port,
+			Protocol: v1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
 func NewServiceForDomain(domain *types.WebLogicDomain) *v1.Service {
-	weblogicPort := v1.ServicePort{Port: 7001}
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: map[string]string{
@@ -56,7 +92,7 @@ func NewServiceForDomain(domain *types.WebLogicDomain) *v1.Service {
 		},
 		Spec: v1.ServiceSpec{
 			Type:  v1.ServiceTypeNodePort,
-			Ports: []v1.ServicePort{weblogicPort},
+			Ports: adminServicePorts(domain),
 			Selector: map[string]string{
 				constants.WebLogicDomainLabel: domain.Name,
 			},
@@ -68,7 +104,7 @@ func NewServiceForDomain(domain *types.WebLogicDomain) *v1.Service {
 func NewHeadlessServiceForDomain(domain *types.WebLogicDomain) *v1.Service {
 	weblogicPort := v1.ServicePort{
 		Name:     domain.Name,
-		Port:     7001,
+		Port:     domain.AdminPort(),
 		Protocol: v1.ProtocolTCP,
 	}
 	svc := &v1.Service{
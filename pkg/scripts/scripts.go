@@ -0,0 +1,63 @@
+// Package scripts holds the scripts run in the server pods, which the operator
+// delivers to every domain through a ConfigMap.
+package scripts
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/glog"
+	"weblogic-operator/pkg/util/hash"
+)
+
+var (
+	mutex    sync.RWMutex
+	contents = map[string]string{}
+	digest   = hash.ForMap(contents)
+)
+
+// Load reads the scripts from the files in dir. Subdirectories are ignored.
+func Load(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("unable to read scripts from %s: %s", dir, err)
+	}
+
+	loaded := map[string]string{}
+	for _, file := range files {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return fmt.Errorf("unable to read script %s: %s", file.Name(), err)
+		}
+		loaded[file.Name()] = string(content)
+	}
+	if len(loaded) == 0 {
+		return fmt.Errorf("no scripts found in %s", dir)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	contents = loaded
+	digest = hash.ForMap(loaded)
+	glog.Infof("Loaded %d scripts from %s, version %s", len(loaded), dir, digest)
+	return nil
+}
+
+// Contents returns the scripts by file name. Callers must not modify them.
+func Contents() map[string]string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return contents
+}
+
+// Hash returns a digest of the scripts, identifying their version.
+func Hash() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return digest
+}
@@ -1,14 +1,16 @@
 package server
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/golang/glog"
-	"k8s.io/api/autoscaling/v1"
+	"k8s.io/api/autoscaling/v2alpha1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -96,14 +98,14 @@ func NewController(kubeClient kubernetes.Interface, restClient *rest.RESTClient,
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
 				options.LabelSelector = constants.WebLogicManagedServerLabel
-				return kubeClient.AutoscalingV1().HorizontalPodAutoscalers(namespace).List(options)
+				return kubeClient.AutoscalingV2alpha1().HorizontalPodAutoscalers(namespace).List(options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 				options.LabelSelector = constants.WebLogicManagedServerLabel
-				return kubeClient.AutoscalingV1().HorizontalPodAutoscalers(namespace).Watch(options)
+				return kubeClient.AutoscalingV2alpha1().HorizontalPodAutoscalers(namespace).Watch(options)
 			},
 		},
-		&v1.HorizontalPodAutoscaler{},
+		&v2alpha1.HorizontalPodAutoscaler{},
 		resyncPeriod,
 		horizontalPodAutoscalerHandler)
 
@@ -140,6 +142,10 @@ func (m *WebLogicManagedServerController) onUpdate(old, cur interface{}) {
 		// different RVs.
 		return
 	}
+	if statusOnlyUpdate(oldServer, curServer) {
+		// The operator recorded restarts or the next schedule, nothing to reconcile
+		return
+	}
 
 	err := updateWebLogicManagedServer(curServer, m.client, m.restClient)
 	if err != nil {
@@ -147,6 +153,18 @@ func (m *WebLogicManagedServerController) onUpdate(old, cur interface{}) {
 	}
 }
 
+// statusOnlyUpdate returns true if the update of a server set left everything
+// but its status unchanged. A schedule firing changes the servers to run
+// through the status, which is reconciled. The populated domain is ignored.
+func statusOnlyUpdate(old, cur *types.WebLogicManagedServer) bool {
+	oldSpec, curSpec := old.Spec, cur.Spec
+	oldSpec.Domain, curSpec.Domain = types.WebLogicDomain{}, types.WebLogicDomain{}
+	return reflect.DeepEqual(oldSpec, curSpec) &&
+		reflect.DeepEqual(old.Labels, cur.Labels) &&
+		reflect.DeepEqual(old.Annotations, cur.Annotations) &&
+		reflect.DeepEqual(old.Status.ScheduledServersToRun, cur.Status.ScheduledServersToRun)
+}
+
 func (m *WebLogicManagedServerController) onReplicaSetAdd(obj interface{}) {
 	glog.V(4).Info("WebLogicManagedServerController.onReplicaSetAdd() called")
 
@@ -179,7 +197,7 @@ func (m *WebLogicManagedServerController) onReplicaSetUpdate(old, new interface{
 func (m *WebLogicManagedServerController) onHorizontalPodAutoscalerAdd(obj interface{}) {
 	glog.V(4).Info("WebLogicManagedServerController.onHorizontalPodAutoscalerAdd() called")
 
-	horizontalPodAutoscaler := obj.(*v1.HorizontalPodAutoscaler)
+	horizontalPodAutoscaler := obj.(*v2alpha1.HorizontalPodAutoscaler)
 
 	weblogicManagedServer, err := GetServerForHorizontalPodAutoscaler(horizontalPodAutoscaler, m.restClient)
 	if err != nil {
@@ -205,12 +223,25 @@ func (m *WebLogicManagedServerController) onHorizontalPodAutoscalerUpdate(old, n
 	m.onHorizontalPodAutoscalerAdd(new)
 }
 
+// evaluateSchedules applies the due schedules of all managed server sets.
+func (m *WebLogicManagedServerController) evaluateSchedules() {
+	now := time.Now()
+	for _, obj := range m.weblogicManagedServerStore.List() {
+		weblogicManagedServer := obj.(*types.WebLogicManagedServer)
+		err := EvaluateSchedulesForWebLogicManagedServer(m.client, m.restClient, weblogicManagedServer, now)
+		if err != nil {
+			glog.Errorf("Failed to evaluate schedules of server %s: %s", weblogicManagedServer.Name, err)
+		}
+	}
+}
+
 // Run the WebLogic controller
 func (m *WebLogicManagedServerController) Run(stopChan <-chan struct{}) {
 	glog.Infof("Starting WebLogic controller")
 	go m.weblogicManagedServerController.Run(stopChan)
 	go m.weblogicManagedServerReplicaSet.Run(stopChan)
 	go m.weblogicManagedServerHorizontalPodAutoscaling.Run(stopChan)
+	go wait.Until(m.evaluateSchedules, ScheduleInterval, stopChan)
 	<-stopChan
 	glog.Infof("Shutting down WebLogic controller")
 }
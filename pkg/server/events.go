@@ -0,0 +1,21 @@
+package server
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/events"
+)
+
+// RecordEventForWebLogicManagedServer records an Event on the given WebLogicManagedServer.
+func RecordEventForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer, eventType, reason, message string) {
+	events.Record(clientset, v1.ObjectReference{
+		APIVersion:      constants.WebLogicGroupName + "/" + constants.WebLogicManagedServerSchemeVersion,
+		Kind:            constants.WebLogicManagedServerResourceKind,
+		Namespace:       server.Namespace,
+		Name:            server.Name,
+		UID:             server.UID,
+		ResourceVersion: server.ResourceVersion,
+	}, eventType, reason, message)
+}
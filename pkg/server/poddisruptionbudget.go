@@ -0,0 +1,64 @@
+package server
+
+import (
+	"github.com/golang/glog"
+	"k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/resources/poddisruptionbudgets"
+	"weblogic-operator/pkg/types"
+)
+
+// CreateOrReplacePodDisruptionBudget creates the given PodDisruptionBudget,
+// replacing an existing one whose spec differs since the spec is immutable.
+func CreateOrReplacePodDisruptionBudget(clientset kubernetes.Interface, pdb *v1beta1.PodDisruptionBudget) error {
+	client := clientset.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace)
+
+	existing, err := client.Get(pdb.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Unable to get pod disruption budget %s: %s", pdb.Name, err)
+		return err
+	}
+
+	if err == nil {
+		if !poddisruptionbudgets.SpecChanged(existing, pdb) {
+			return nil
+		}
+		glog.V(2).Infof("Replacing pod disruption budget %s", pdb.Name)
+		err = client.Delete(pdb.Name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	glog.V(4).Infof("Creating pod disruption budget %s", pdb.Name)
+	_, err = client.Create(pdb)
+	return err
+}
+
+// DeletePodDisruptionBudget deletes the named PodDisruptionBudget, if any.
+func DeletePodDisruptionBudget(clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.PolicyV1beta1().PodDisruptionBudgets(namespace).Delete(name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete pod disruption budget %s: %s", name, err)
+		return err
+	}
+	return nil
+}
+
+// CreateOrUpdatePodDisruptionBudgetForWebLogicManagedServer keeps the
+// PodDisruptionBudget of a server in line with its spec and replica count.
+func CreateOrUpdatePodDisruptionBudgetForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer) error {
+	if err := server.Spec.DisruptionBudget.Validate(); err != nil {
+		glog.Errorf("Invalid disruption budget for server %s: %s", server.Name, err)
+		return err
+	}
+	return CreateOrReplacePodDisruptionBudget(clientset, poddisruptionbudgets.NewForServer(server))
+}
+
+// DeletePodDisruptionBudgetForWebLogicManagedServer deletes the PodDisruptionBudget of a server.
+func DeletePodDisruptionBudgetForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer) error {
+	return DeletePodDisruptionBudget(clientset, server.Namespace, server.Name)
+}
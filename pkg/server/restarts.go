@@ -0,0 +1,222 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/wlsrest"
+)
+
+var (
+	restartsLock sync.Mutex
+	// restartsInProgress holds the failed servers being restarted
+	restartsInProgress = map[string]bool{}
+)
+
+func restartKey(namespace, name, serverName string) string {
+	return namespace + "/" + name + "/" + serverName
+}
+
+func restartInProgress(key string) bool {
+	restartsLock.Lock()
+	defer restartsLock.Unlock()
+	return restartsInProgress[key]
+}
+
+// restartStatus returns a copy of the restart status of the named server with
+// the restarts that left the window dropped.
+func restartStatus(server *types.WebLogicManagedServer, serverName string, now time.Time) types.ServerRestartStatus {
+	status := types.ServerRestartStatus{Name: serverName}
+	for _, restarts := range server.Status.Restarts {
+		if restarts.Name == serverName {
+			status = restarts
+			break
+		}
+	}
+
+	windowStart := now.Add(-server.Spec.RestartPolicy.Window())
+	var recent []metav1.Time
+	for _, restart := range status.RecentRestarts {
+		if restart.Time.After(windowStart) {
+			recent = append(recent, restart)
+		}
+	}
+	status.RecentRestarts = recent
+	return status
+}
+
+func sameRestartStatus(a, b types.ServerRestartStatus) bool {
+	if a.RestartCount != b.RestartCount || a.BudgetExhausted != b.BudgetExhausted ||
+		a.RestartInProgress != b.RestartInProgress || len(a.RecentRestarts) != len(b.RecentRestarts) {
+		return false
+	}
+	for i := range a.RecentRestarts {
+		if !a.RecentRestarts[i].Time.Equal(b.RecentRestarts[i].Time) {
+			return false
+		}
+	}
+	return true
+}
+
+// restartFailedServer restarts a failed server through the Node Manager of its
+// pod when the domain runs one, falling back to deleting the pod. Returns what
+// was restarted.
+func restartFailedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer, serverName string, pod *v1.Pod) (string, error) {
+	// Do not send the domain back with the status
+	populated := *server
+	domain := &populated.PopulateDomain().Spec.Domain
+	if domain.NodeManagerEnabled() {
+		client, err := wlsrest.NewClientForDomain(clientset, domain)
+		if err == nil {
+			err = client.RestartServer(serverName)
+		}
+		if err == nil {
+			return "the JVM in pod " + pod.Name, nil
+		}
+		glog.Errorf("Could not restart %s through its Node Manager, deleting its pod: %s", serverName, err)
+	}
+
+	err := clientset.CoreV1().Pods(pod.Namespace).Delete(pod.Name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	return "pod " + pod.Name, nil
+}
+
+// restartFailedServerAsync runs restartFailedServer in the background, which
+// may wait for the server to start, and reports the outcome once the restart
+// completes.
+func restartFailedServerAsync(clientset kubernetes.Interface, restClient *rest.RESTClient, server *types.WebLogicManagedServer, serverName string, pod *v1.Pod, restartCount int) {
+	key := restartKey(server.Namespace, server.Name, serverName)
+	restartsLock.Lock()
+	restartsInProgress[key] = true
+	restartsLock.Unlock()
+
+	go func() {
+		defer func() {
+			restartsLock.Lock()
+			delete(restartsInProgress, key)
+			restartsLock.Unlock()
+		}()
+
+		policy := server.Spec.RestartPolicy
+		restarted, err := restartFailedServer(clientset, server, serverName, pod)
+		if err != nil {
+			glog.Errorf("Could not restart failed server %s: %s", serverName, err)
+			RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeWarning, "ServerRestartFailed",
+				fmt.Sprintf("Could not restart failed server %s: %s", serverName, err))
+		} else {
+			message := fmt.Sprintf("Restarted %s of failed server %s, restart %d of %d within %s",
+				restarted, serverName, restartCount, policy.MaxRestartsInWindow(), policy.Window())
+			glog.V(2).Info(message)
+			RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeWarning, "ServerRestarted", message)
+		}
+
+		err = clearRestartInProgress(restClient, server.Namespace, server.Name, serverName)
+		if err != nil {
+			glog.Errorf("Could not record the end of the restart of %s: %s", serverName, err)
+		}
+	}()
+}
+
+// clearRestartInProgress records in the status of a server set that the
+// restart of one of its servers completed.
+func clearRestartInProgress(restClient *rest.RESTClient, namespace, name, serverName string) error {
+	server, err := GetWebLogicManagedServer(restClient, namespace, name)
+	if err != nil {
+		return err
+	}
+	for i := range server.Status.Restarts {
+		if server.Status.Restarts[i].Name == serverName && server.Status.Restarts[i].RestartInProgress {
+			server.Status.Restarts[i].RestartInProgress = false
+			return putWebLogicManagedServer(server, restClient)
+		}
+	}
+	return nil
+}
+
+// RestartFailedServerForWebLogicManagedServer applies the restart policy of a
+// server set to one of its servers that entered FAILED. The server is
+// restarted through its Node Manager or else its pod is deleted so the
+// ReplicaSet starts a fresh JVM, unless the server was
+// restarted too recently or used up the restarts allowed within the window,
+// which is reported once with a Warning event. The restart runs in the
+// background and is tracked in the status of the set until it completes.
+// Returns false if the set has no restart policy.
+func RestartFailedServerForWebLogicManagedServer(clientset kubernetes.Interface, restClient *rest.RESTClient, namespace, name, serverName string, pod *v1.Pod, now time.Time) (bool, error) {
+	server, err := GetWebLogicManagedServer(restClient, namespace, name)
+	if err != nil {
+		return false, err
+	}
+	policy := server.Spec.RestartPolicy
+	if policy == nil {
+		return false, nil
+	}
+
+	status := restartStatus(server, serverName, now)
+	recent := len(status.RecentRestarts)
+	// A restart left in progress by a previous operator is over
+	status.RestartInProgress = restartInProgress(restartKey(namespace, name, serverName))
+
+	restarting := false
+	switch {
+	case status.RestartInProgress:
+		glog.V(4).Infof("Restart of failed server %s of %s is in progress", serverName, server.Name)
+	case recent >= policy.MaxRestartsInWindow():
+		if !status.BudgetExhausted {
+			status.BudgetExhausted = true
+			message := fmt.Sprintf("Server %s failed again after %d restarts within %s, not restarting it", serverName, recent, policy.Window())
+			glog.V(2).Info(message)
+			RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeWarning, "RestartBudgetExhausted", message)
+		}
+	case recent > 0 && now.Sub(status.RecentRestarts[recent-1].Time) < policy.Backoff(recent):
+		glog.V(4).Infof("Backing off restart of failed server %s of %s", serverName, server.Name)
+		status.BudgetExhausted = false
+	default:
+		status.BudgetExhausted = false
+		status.RestartCount++
+		status.RecentRestarts = append(status.RecentRestarts, metav1.NewTime(now))
+		status.RestartInProgress = true
+		restarting = true
+	}
+
+	// Compare against the stored status, not the trimmed one, so restarts
+	// leaving the window are dropped from it as well
+	var stored types.ServerRestartStatus
+	found := false
+	var restarts []types.ServerRestartStatus
+	for _, r := range server.Status.Restarts {
+		if r.Name == serverName {
+			stored, found = r, true
+			restarts = append(restarts, status)
+			continue
+		}
+		restarts = append(restarts, r)
+	}
+	if !found {
+		restarts = append(restarts, status)
+	}
+	if found && sameRestartStatus(stored, status) {
+		return true, nil
+	}
+
+	server.Status.Restarts = restarts
+	err = putWebLogicManagedServer(server, restClient)
+	if err != nil {
+		// Not restarting unrecorded, the next health check retries
+		glog.Errorf("Could not record restarts of %s: %s", server.Name, err)
+		return true, err
+	}
+	if restarting {
+		restartFailedServerAsync(clientset, restClient, server, serverName, pod, len(status.RecentRestarts))
+	}
+	return true, nil
+}
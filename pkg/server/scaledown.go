@@ -0,0 +1,273 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/restart"
+	"weblogic-operator/pkg/wlsrest"
+)
+
+// ScaleDownNotReadyTimeout bounds how long a scale down waits for the pods of
+// the shut down servers to report not ready.
+var ScaleDownNotReadyTimeout = 60 * time.Second
+
+var (
+	// scaleDownsLock is held while the replicas of a ReplicaSet are written
+	scaleDownsLock sync.Mutex
+	// scaleDowns holds the replicas ReplicaSets scale to once the scale down
+	// in progress shut their servers down
+	scaleDowns = map[string]int32{}
+)
+
+type scaleDownCandidate struct {
+	pod        v1.Pod
+	serverName string
+	sessions   int
+}
+
+// scaleDownCandidates returns the ready pods of the ReplicaSet hosting a
+// WebLogic server.
+func scaleDownCandidates(clientset kubernetes.Interface, rs *v1beta1.ReplicaSet) ([]scaleDownCandidate, error) {
+	opts := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(rs.Spec.Selector.MatchLabels).String()}
+	pods, err := clientset.CoreV1().Pods(rs.Namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []scaleDownCandidate
+	for _, pod := range pods.Items {
+		serverName := pod.Labels[constants.WebLogicServerNameLabel]
+		if serverName == "" || !restart.IsPodReady(&pod) {
+			continue
+		}
+		candidates = append(candidates, scaleDownCandidate{pod: pod, serverName: serverName})
+	}
+	return candidates, nil
+}
+
+// orderForScaleDown sorts the candidates so the servers to remove come first.
+func orderForScaleDown(candidates []scaleDownCandidate, policy types.ScaleDownPolicy) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if policy == types.ScaleDownLeastSessions && candidates[i].sessions != candidates[j].sessions {
+			return candidates[i].sessions < candidates[j].sessions
+		}
+		return types.ManagedServerNumber(candidates[i].serverName) > types.ManagedServerNumber(candidates[j].serverName)
+	})
+}
+
+// markForScaleDown annotates the pod of a server being removed, so neither
+// health checks nor rolling restarts start its server again.
+func markForScaleDown(clientset kubernetes.Interface, pod *v1.Pod) error {
+	current, err := clientset.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	current.Annotations[constants.ScaleDownAnnotation] = "true"
+	_, err = clientset.CoreV1().Pods(pod.Namespace).Update(current)
+	return err
+}
+
+// ShutdownServersForScaleDown removes servers itself when the ReplicaSet of a
+// server set is about to shrink to the given number of replicas. Following
+// the scaleDownPolicy, by default the highest numbered servers are marked,
+// suspended and gracefully shut down, all at once and within the shutdown
+// timeout. The ReplicaSet removes pods that are not ready first, so it then
+// deletes the pods of these servers rather than arbitrary ones, and the
+// running servers stay numbered contiguously. When the admin server cannot be
+// reached the ReplicaSet chooses.
+func ShutdownServersForScaleDown(clientset kubernetes.Interface, server *types.WebLogicManagedServer, rs *v1beta1.ReplicaSet, replicas int32) error {
+	if rs.Spec.Replicas == nil || *rs.Spec.Replicas <= replicas {
+		return nil
+	}
+	count := int(*rs.Spec.Replicas - replicas)
+
+	candidates, err := scaleDownCandidates(clientset, rs)
+	if err != nil {
+		glog.Errorf("Unable to list pods of %s: %s", rs.Name, err)
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	client, err := wlsrest.NewClientForDomain(clientset, &server.Spec.Domain)
+	if err != nil {
+		glog.Errorf("Unable to reach the admin server of %s to scale down %s: %s", server.Spec.DomainName, server.Name, err)
+		return nil
+	}
+	if server.Spec.ScaleDownPolicy == types.ScaleDownLeastSessions {
+		sessions, err := client.OpenSessions()
+		if err != nil {
+			glog.Errorf("Unable to read the open sessions of %s, leaving the choice of servers to the replica set: %s", server.Name, err)
+			return nil
+		}
+		for i := range candidates {
+			candidates[i].sessions = sessions[candidates[i].serverName]
+		}
+	}
+	orderForScaleDown(candidates, server.Spec.ScaleDownPolicy)
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mutex   sync.Mutex
+		stopped []v1.Pod
+	)
+	for _, candidate := range candidates[:count] {
+		wg.Add(1)
+		go func(candidate scaleDownCandidate) {
+			defer wg.Done()
+			if shutdownForScaleDown(clientset, client, server, candidate) {
+				mutex.Lock()
+				stopped = append(stopped, candidate.pod)
+				mutex.Unlock()
+			}
+		}(candidate)
+	}
+	wg.Wait()
+
+	return waitForPodsNotReady(clientset, stopped)
+}
+
+// shutdownForScaleDown marks, suspends and shuts down the server of a
+// candidate. The shutdown only gets the part of the timeout the suspension
+// left, and is forced once it is used up. Returns true if the server is down.
+func shutdownForScaleDown(clientset kubernetes.Interface, client *wlsrest.Client, server *types.WebLogicManagedServer, candidate scaleDownCandidate) bool {
+	glog.V(2).Infof("Shutting down %s in pod %s to scale down %s", candidate.serverName, candidate.pod.Name, server.Name)
+	err := markForScaleDown(clientset, &candidate.pod)
+	if err != nil {
+		glog.Errorf("Could not mark pod %s for scale down: %s", candidate.pod.Name, err)
+	}
+
+	// Stop taking new work before shutting down
+	started := time.Now()
+	err = client.SuspendServer(candidate.serverName, server.Spec.Shutdown)
+	if err != nil {
+		glog.Errorf("Could not suspend %s: %s", candidate.serverName, err)
+	}
+
+	// Sessions were drained by the suspension, as far as they will be
+	remaining := server.Spec.Shutdown.Timeout() - int64(time.Since(started).Seconds())
+	shutdown := &types.ShutdownOptions{Type: types.ShutdownForced}
+	if !server.Spec.Shutdown.Forced() && remaining > 0 {
+		shutdown = &types.ShutdownOptions{Type: types.ShutdownGraceful, TimeoutSeconds: &remaining, IgnoreSessions: true}
+	}
+	err = client.ShutdownServer(candidate.serverName, shutdown)
+	if err != nil {
+		glog.Errorf("Could not shut down %s: %s", candidate.serverName, err)
+		return false
+	}
+	RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeNormal, "ServerShutdown",
+		fmt.Sprintf("Shut down %s to scale down", candidate.serverName))
+	return true
+}
+
+// scaleDownAsync runs ShutdownServersForScaleDown in the background when the
+// ReplicaSet of a server set is to shrink to the given number of replicas,
+// and then scales the ReplicaSet. While a scale down is in progress the latest
+// replicas are recorded for when it completes. Returns true if the ReplicaSet
+// must keep its current replicas meanwhile. The caller holds scaleDownsLock.
+func scaleDownAsync(clientset kubernetes.Interface, server *types.WebLogicManagedServer, rs *v1beta1.ReplicaSet, replicas int32) bool {
+	key := rs.Namespace + "/" + rs.Name
+	if _, found := scaleDowns[key]; found {
+		glog.V(2).Infof("Scale down of %s is already in progress, scaling to %d replicas when it completes", key, replicas)
+		scaleDowns[key] = replicas
+		return true
+	}
+	if rs.Spec.Replicas == nil || *rs.Spec.Replicas <= replicas {
+		return false
+	}
+	scaleDowns[key] = replicas
+
+	// Do not share the cached server with the handlers
+	go func(server types.WebLogicManagedServer) {
+		err := ShutdownServersForScaleDown(clientset, &server, rs, replicas)
+		if err != nil {
+			glog.Errorf("Could not shut down the servers removed from %s: %s", key, err)
+		}
+
+		scaleDownsLock.Lock()
+		defer scaleDownsLock.Unlock()
+		replicas := scaleDowns[key]
+		delete(scaleDowns, key)
+		err = scaleReplicaSet(clientset, rs.Namespace, rs.Name, replicas)
+		if err != nil {
+			glog.Errorf("Could not scale %s to %d replicas: %s", key, replicas, err)
+			RecordEventForWebLogicManagedServer(clientset, &server, v1.EventTypeWarning, "ScaleDownFailed",
+				fmt.Sprintf("Could not scale to %d servers: %s", replicas, err))
+		}
+	}(*server)
+	return true
+}
+
+// ScaleReplicaSetForWebLogicManagedServer scales the ReplicaSet of a server
+// set to the given number of replicas. Like an update of the set, servers are
+// shut down before the ReplicaSet shrinks.
+func ScaleReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer, replicaSet *v1beta1.ReplicaSet, replicas int32) error {
+	scaleDownsLock.Lock()
+	defer scaleDownsLock.Unlock()
+
+	// Read the replicas again now no scale down writes them
+	rs, err := clientset.ExtensionsV1beta1().ReplicaSets(replicaSet.Namespace).Get(replicaSet.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if scaleDownAsync(clientset, server, rs, replicas) {
+		return nil
+	}
+	if rs.Spec.Replicas != nil && *rs.Spec.Replicas == replicas {
+		return nil
+	}
+	return scaleReplicaSet(clientset, rs.Namespace, rs.Name, replicas)
+}
+
+// scaleReplicaSet sets the replicas of the named ReplicaSet.
+func scaleReplicaSet(clientset kubernetes.Interface, namespace, name string, replicas int32) error {
+	rs, err := clientset.ExtensionsV1beta1().ReplicaSets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	glog.V(2).Infof("Scaling %s/%s to %d replicas", namespace, name, replicas)
+	rs.Spec.Replicas = &replicas
+	_, err = clientset.ExtensionsV1beta1().ReplicaSets(namespace).Update(rs)
+	return err
+}
+
+// waitForPodsNotReady waits until the readiness probes noticed the servers of
+// the given pods are down, so the ReplicaSet picks these pods.
+func waitForPodsNotReady(clientset kubernetes.Interface, pods []v1.Pod) error {
+	for _, pod := range pods {
+		err := wait.PollImmediate(2*time.Second, ScaleDownNotReadyTimeout, func() (bool, error) {
+			current, err := clientset.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return !restart.IsPodReady(current), nil
+		})
+		if err == wait.ErrWaitTimeout {
+			glog.Errorf("Pod %s still reports ready after its server was shut down", pod.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+// Attempts to update serversToRun when the server was modified concurrently
+const scaleRetries = 3
+
+// ScalingRejectedError is returned when a scaling request cannot be applied to
+// a WebLogicManagedServer in its current state.
+type ScalingRejectedError struct {
+	Reason string
+}
+
+func (e *ScalingRejectedError) Error() string {
+	return e.Reason
+}
+
+// IsScalingRejected returns true if the error rejected a scaling request.
+func IsScalingRejected(err error) bool {
+	_, ok := err.(*ScalingRejectedError)
+	return ok
+}
+
+// GetWebLogicManagedServer fetches a WebLogicManagedServer through the API.
+func GetWebLogicManagedServer(restClient *rest.RESTClient, namespace, name string) (*types.WebLogicManagedServer, error) {
+	server := &types.WebLogicManagedServer{}
+	err := restClient.Get().
+		Resource(constants.WebLogicManagedServerResourceKindPlural).
+		Namespace(namespace).
+		Name(name).
+		Do().
+		Into(server)
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// ScaleWebLogicManagedServer changes serversToRun of the WebLogicManagedServer
+// running the given cluster of a domain by delta, within the bounds of the
+// server set. The outcome is recorded as an Event on the server set.
+func ScaleWebLogicManagedServer(clientset kubernetes.Interface, restClient *rest.RESTClient, namespace, domainName, clusterName string, delta int32) (*types.WebLogicManagedServer, error) {
+	var err error
+	for i := 0; i < scaleRetries; i++ {
+		var server *types.WebLogicManagedServer
+		server, err = GetWebLogicManagedServer(restClient, namespace, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if server.Spec.DomainName != domainName {
+			return nil, errors.NewNotFound(types.WeblogicManagedServerSchemeGroupVersion.WithResource(constants.WebLogicManagedServerResourceKindPlural).GroupResource(), clusterName)
+		}
+
+		err = scaleWebLogicManagedServer(clientset, restClient, server, delta)
+		if err == nil || !errors.IsConflict(err) {
+			return server, err
+		}
+		glog.V(4).Infof("Server %s was modified while scaling, retrying", server.Name)
+	}
+	return nil, err
+}
+
+func scaleWebLogicManagedServer(clientset kubernetes.Interface, restClient *rest.RESTClient, server *types.WebLogicManagedServer, delta int32) error {
+	// Only read the domain for the bounds, the stored server is written back as is
+	populated := *server
+	err := populated.FetchDomain()
+	if err != nil {
+		return err
+	}
+	minReplicas, maxReplicas := populated.ScalingBounds()
+
+	current := server.Spec.ServersToRun
+	desired := current + delta
+
+	var rejected string
+	switch {
+	case !populated.ServersStarted():
+		rejected = fmt.Sprintf("servers of %s are stopped by a serverStartPolicy", server.Name)
+	case server.AutoscalingEnabled():
+		rejected = fmt.Sprintf("%s is scaled by its HorizontalPodAutoscaler, disable autoscaling to scale it from WebLogic", server.Name)
+	case len(server.Spec.Schedules) > 0:
+		rejected = fmt.Sprintf("%s is scaled by its schedules", server.Name)
+	case desired < minReplicas:
+		rejected = fmt.Sprintf("scaling %s from %d to %d servers would fall below its minimum of %d", server.Name, current, desired, minReplicas)
+	case desired > maxReplicas:
+		rejected = fmt.Sprintf("scaling %s from %d to %d servers would exceed its maximum of %d", server.Name, current, desired, maxReplicas)
+	}
+	if rejected != "" {
+		glog.V(2).Infof("Rejected scaling request: %s", rejected)
+		RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeWarning, "ScalingRejected", rejected)
+		return &ScalingRejectedError{Reason: rejected}
+	}
+
+	server.Spec.ServersToRun = desired
+	err = putWebLogicManagedServer(server, restClient)
+	if err != nil {
+		return err
+	}
+
+	glog.V(2).Infof("Scaled %s from %d to %d servers", server.Name, current, desired)
+	RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeNormal, "Scaled", fmt.Sprintf("Scaled from %d to %d servers", current, desired))
+	return nil
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/cron"
+)
+
+// ScheduleInterval is how often the schedules of managed server sets are evaluated.
+const ScheduleInterval = time.Minute
+
+// nextScheduledScaling returns the first schedule firing after the given time.
+func nextScheduledScaling(server *types.WebLogicManagedServer, after time.Time) (*types.ScheduledScaling, error) {
+	var next *types.ScheduledScaling
+	for _, schedule := range server.Spec.Schedules {
+		parsed, err := cron.Parse(schedule.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %v", schedule.ScheduleName(), err)
+		}
+		fires := parsed.Next(after)
+		if fires.IsZero() {
+			continue
+		}
+		if next == nil || fires.Before(next.Time.Time) {
+			next = &types.ScheduledScaling{
+				Schedule:     schedule.ScheduleName(),
+				ServersToRun: schedule.ServersToRun,
+				Time:         metav1.NewTime(fires),
+			}
+		}
+	}
+	return next, nil
+}
+
+// lastScheduledScaling returns the last schedule firing at or before the
+// given time.
+func lastScheduledScaling(server *types.WebLogicManagedServer, at time.Time) (*types.ScheduledScaling, error) {
+	var last *types.ScheduledScaling
+	for _, schedule := range server.Spec.Schedules {
+		parsed, err := cron.Parse(schedule.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %v", schedule.ScheduleName(), err)
+		}
+		fired := parsed.Prev(at)
+		if fired.IsZero() {
+			continue
+		}
+		if last == nil || fired.After(last.Time.Time) {
+			last = &types.ScheduledScaling{
+				Schedule:     schedule.ScheduleName(),
+				ServersToRun: schedule.ServersToRun,
+				Time:         metav1.NewTime(fired),
+			}
+		}
+	}
+	return last, nil
+}
+
+func sameScheduledScaling(a, b *types.ScheduledScaling) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	// Times read back from the API are in another location, compare instants
+	return a.Schedule == b.Schedule && a.ServersToRun == b.ServersToRun && a.Time.Time.Equal(b.Time.Time)
+}
+
+func sameStatus(a, b *types.WebLogicManagedServerStatus) bool {
+	sameServersToRun := reflect.DeepEqual(a.ScheduledServersToRun, b.ScheduledServersToRun)
+	return sameServersToRun &&
+		sameScheduledScaling(a.LastScheduledScaling, b.LastScheduledScaling) &&
+		sameScheduledScaling(a.NextScheduledScaling, b.NextScheduledScaling)
+}
+
+// EvaluateSchedulesForWebLogicManagedServer applies the last scaling of the
+// schedules of a managed server set that is not applied yet and records the
+// next one in its status. A set created or whose schedules changed thereby
+// gets the number of servers of the current window right away. The resulting
+// update of the server scales its ReplicaSet.
+func EvaluateSchedulesForWebLogicManagedServer(clientset kubernetes.Interface, restClient *rest.RESTClient, server *types.WebLogicManagedServer, now time.Time) error {
+	// Do not modify the cached server
+	updated := *server
+	status := &updated.Status
+
+	if len(server.Spec.Schedules) == 0 {
+		status.ScheduledServersToRun = nil
+		status.LastScheduledScaling = nil
+		status.NextScheduledScaling = nil
+	} else {
+		last, err := lastScheduledScaling(server, now)
+		if err != nil {
+			return err
+		}
+		if last != nil && !sameScheduledScaling(last, server.Status.LastScheduledScaling) {
+			glog.V(2).Infof("Schedule %s scales %s to %d servers", last.Schedule, server.Name, last.ServersToRun)
+			serversToRun := last.ServersToRun
+			status.ScheduledServersToRun = &serversToRun
+			status.LastScheduledScaling = last
+			RecordEventForWebLogicManagedServer(clientset, server, v1.EventTypeNormal, "ScheduledScaling",
+				fmt.Sprintf("Schedule %s scaled to %d servers", last.Schedule, last.ServersToRun))
+		}
+
+		next, err := nextScheduledScaling(server, now)
+		if err != nil {
+			return err
+		}
+		status.NextScheduledScaling = next
+	}
+
+	if sameStatus(status, &server.Status) {
+		return nil
+	}
+	return putWebLogicManagedServer(&updated, restClient)
+}
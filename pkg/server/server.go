@@ -3,21 +3,25 @@ package server
 import (
 	"fmt"
 
-	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/api/autoscaling/v2alpha1"
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
 	"github.com/golang/glog"
 
+	"reflect"
 	"strings"
 	"weblogic-operator/pkg/constants"
 	"weblogic-operator/pkg/resources/horizontalpodautoscalers"
 	"weblogic-operator/pkg/resources/replicasets"
 	"weblogic-operator/pkg/resources/services"
 	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/util/restart"
 )
 
 // HasServerNameLabel returns true if the given labels map matches the given
@@ -77,6 +81,10 @@ func CreateReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, se
 }
 
 func UpdateReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer, service *v1.Service) (controller *v1beta1.ReplicaSet, err error) {
+	// Scale downs in the background write the replicas as well
+	scaleDownsLock.Lock()
+	defer scaleDownsLock.Unlock()
+
 	// Find ReplicaSet and if it does not exist create it
 	existingReplicaSet, err := GetReplicaSetForWebLogicManagedServer(server, clientset)
 	if err != nil {
@@ -89,9 +97,27 @@ func UpdateReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, se
 
 		glog.V(4).Infof("Creating updated replica set for server %s", server.Name)
 		rs := replicasets.NewForServer(server, service.Name)
+		replicas := server.ReplicaSetReplicas(existingReplicaSet.Spec.Replicas)
+
+		// Choose the servers to remove rather than leaving it to the replica
+		// set, which keeps its replicas until they are shut down
+		if scaleDownAsync(clientset, server, existingReplicaSet, replicas) {
+			replicas = *existingReplicaSet.Spec.Replicas
+		}
+		rs.Spec.Replicas = &replicas
 
 		glog.V(4).Infof("Creating server %+v", rs)
-		return clientset.ExtensionsV1beta1().ReplicaSets(server.Namespace).Update(rs)
+		updated, err := clientset.ExtensionsV1beta1().ReplicaSets(server.Namespace).Update(rs)
+		if err != nil {
+			return nil, err
+		}
+
+		// A replica set keeps its pods when the template changes
+		if replicasets.TemplateChanged(existingReplicaSet, rs) {
+			glog.V(2).Infof("Pod template of %s changed, restarting its servers", server.Name)
+			restart.RollingRestartAsync(clientset, server.Namespace, labels.SelectorFromSet(rs.Spec.Selector.MatchLabels).String())
+		}
+		return updated, nil
 	}
 
 	return nil, nil
@@ -100,12 +126,9 @@ func UpdateReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, se
 // DeleteReplicaSetForWebLogicManagedServer will delete a replica set by name
 func DeleteReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer) error {
 
-	if strings.EqualFold(server.Name, constants.HorizontalPodAutoscalerTargetLabel) {
-		glog.V(4).Infof("Deleting HPA for managed server!!!!!!!!!!!!!")
-		err := DeleteHorizontalPodAutoscalerForWebLogicManagedServer(clientset, server)
-		if err != nil {
-			return err
-		}
+	err := DeleteHorizontalPodAutoscalerForWebLogicManagedServer(clientset, server)
+	if err != nil {
+		return err
 	}
 
 	replicaSet, err := GetReplicaSetForWebLogicManagedServer(server, clientset)
@@ -121,64 +144,95 @@ func DeleteReplicaSetForWebLogicManagedServer(clientset kubernetes.Interface, se
 		Delete(replicaSet.Name, &metav1.DeleteOptions{PropagationPolicy: &policy})
 }
 
-// GetHorizontalPodAutoscalerForWebLogicManagedServer finds the associated ReplicaSet for a Weblogic server
-func GetHorizontalPodAutoscalerForWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient kubernetes.Interface) (*autoscalingv1.HorizontalPodAutoscaler, error) {
-	opts := metav1.ListOptions{LabelSelector: getLabelSelectorForServer(server)}
-	horizontalpodautoscalers, err := kubeClient.AutoscalingV1().HorizontalPodAutoscalers(server.Namespace).List(opts)
+// GetHorizontalPodAutoscalerForWebLogicManagedServer finds the associated HorizontalPodAutoscaler for a Weblogic server
+func GetHorizontalPodAutoscalerForWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient kubernetes.Interface) (*v2alpha1.HorizontalPodAutoscaler, error) {
+	hpa, err := kubeClient.AutoscalingV2alpha1().HorizontalPodAutoscalers(server.Namespace).Get(horizontalpodautoscalers.Name(server), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
 	if err != nil {
-		glog.Errorf("Unable to list horizontal pod autoscalers for %s: %s", server.Name, err)
+		glog.Errorf("Unable to get horizontal pod autoscaler for %s: %s", server.Name, err)
 		return nil, err
 	}
+	return hpa, nil
+}
 
-	for _, rc := range horizontalpodautoscalers.Items {
-		if HasServerNameLabel(rc.Labels, server.Name) {
-			glog.V(4).Infof("Value of RC!!!")
-			return &rc, nil
-		}
+// CreateOrUpdateHorizontalPodAutoscalerForWebLogicManagedServer keeps the
+// HorizontalPodAutoscaler of a server in line with its autoscaling settings,
+// deleting it when autoscaling is disabled or the servers are stopped.
+func CreateOrUpdateHorizontalPodAutoscalerForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer) (*v2alpha1.HorizontalPodAutoscaler, error) {
+	if !server.AutoscalingEnabled() || !server.ServersStarted() {
+		return nil, DeleteHorizontalPodAutoscalerForWebLogicManagedServer(clientset, server)
 	}
-	return nil, nil
-}
 
-// CreateHorizontalPodAutoscalerForWebLogicManagedServer will create a new Kubernetes HorizontalPodAutoscaler based on a predefined template
-func CreateHorizontalPodAutoscalerForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer, service *v1.Service) (controller *autoscalingv1.HorizontalPodAutoscaler, err error) {
-	// Find ReplicaSet and if it does not exist create it
 	existingHorizontalPodAutoscaler, err := GetHorizontalPodAutoscalerForWebLogicManagedServer(server, clientset)
 	if err != nil {
 		glog.Errorf("Error finding Horizontal Pod Autoscaler for server: %v", err)
 		return nil, err
 	}
 
+	hpa := horizontalpodautoscalers.NewForServer(server)
+
 	if existingHorizontalPodAutoscaler != nil {
-		glog.V(2).Infof("Replica set with label %s already exists", getLabelSelectorForServer(server))
-		return existingHorizontalPodAutoscaler, nil
+		if reflect.DeepEqual(existingHorizontalPodAutoscaler.Spec, hpa.Spec) {
+			return existingHorizontalPodAutoscaler, nil
+		}
+		glog.V(2).Infof("Updating Horizontal Pod Autoscaler %s", hpa.Name)
+		existingHorizontalPodAutoscaler.Spec = hpa.Spec
+		return clientset.AutoscalingV2alpha1().HorizontalPodAutoscalers(server.Namespace).Update(existingHorizontalPodAutoscaler)
 	}
 
-	glog.V(4).Infof("Creating a new Horizontal Pod Autoscalers for server %s", server.Name)
-	rs := horizontalpodautoscalers.NewForHorizontalPodAutoscaling(server, service.Name)
-
-	glog.V(4).Infof("Creating server %+v", rs)
-	return clientset.AutoscalingV1().HorizontalPodAutoscalers(server.Namespace).Create(rs)
+	glog.V(4).Infof("Creating a new Horizontal Pod Autoscaler for server %s", server.Name)
+	return clientset.AutoscalingV2alpha1().HorizontalPodAutoscalers(server.Namespace).Create(hpa)
 }
 
-// DeleteHorizontalPodAutoscalerForWebLogicManagedServer will delete a replica set by name
+// DeleteHorizontalPodAutoscalerForWebLogicManagedServer will delete the HorizontalPodAutoscaler of a server, if any
 func DeleteHorizontalPodAutoscalerForWebLogicManagedServer(clientset kubernetes.Interface, server *types.WebLogicManagedServer) error {
-	horizontalPodAutoscaler, err := GetHorizontalPodAutoscalerForWebLogicManagedServer(server, clientset)
-	glog.V(4).Infof("value of horizontalPodAutoscaler")
-	if err != nil && horizontalPodAutoscaler == nil {
+	glog.V(4).Infof("Deleting Horizontal Pod Autoscaler %s", horizontalpodautoscalers.Name(server))
+	var policy = metav1.DeletePropagationBackground
+	err := clientset.AutoscalingV2alpha1().HorizontalPodAutoscalers(server.Namespace).
+		Delete(horizontalpodautoscalers.Name(server), &metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !errors.IsNotFound(err) {
 		glog.Errorf("Could not delete Horizontal Pod Autoscaler: %s", err)
 		return err
 	}
-
-	glog.V(4).Infof("Deleting Horizontal Pod Autoscaler %s", horizontalPodAutoscaler.Name)
-	var policy = metav1.DeletePropagationBackground
-	return clientset.AutoscalingV1().HorizontalPodAutoscalers(server.Namespace).
-		Delete(horizontalPodAutoscaler.Name, &metav1.DeleteOptions{PropagationPolicy: &policy})
+	return nil
 }
 
 func createWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient kubernetes.Interface, restClient *rest.RESTClient) error {
 	server.EnsureDefaults()
 	server.PopulateDomain()
 
+	err := server.ValidateSchedules()
+	if err != nil {
+		glog.Errorf("Invalid schedules for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.ValidateServerStartPolicy()
+	if err != nil {
+		glog.Errorf("Invalid server start policy for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.Spec.Shutdown.Validate()
+	if err != nil {
+		glog.Errorf("Invalid shutdown options for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.ValidateScaleDownPolicy()
+	if err != nil {
+		glog.Errorf("Invalid scale down policy for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.Spec.RestartPolicy.Validate()
+	if err != nil {
+		glog.Errorf("Invalid restart policy for server %s: %s", server.Name, err)
+		return err
+	}
+
 	// Validate that a label is set on the server
 	if !HasServerNameLabel(server.Labels, server.Name) {
 		glog.V(4).Infof("Setting label on server %s", getLabelSelectorForServer(server))
@@ -200,7 +254,12 @@ func createWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient
 		return err
 	}
 
-	_, err = CreateHorizontalPodAutoscalerForWebLogicManagedServer(kubeClient, server, serverService)
+	_, err = CreateOrUpdateHorizontalPodAutoscalerForWebLogicManagedServer(kubeClient, server)
+	if err != nil {
+		return err
+	}
+
+	err = CreateOrUpdatePodDisruptionBudgetForWebLogicManagedServer(kubeClient, server)
 	if err != nil {
 		return err
 	}
@@ -210,6 +269,39 @@ func createWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient
 
 //TODO update the replica set
 func updateWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient kubernetes.Interface, restClient *rest.RESTClient) error {
+	// The pod template depends on the current domain, e.g. its config overrides
+	server.PopulateDomain()
+
+	err := server.ValidateSchedules()
+	if err != nil {
+		glog.Errorf("Invalid schedules for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.ValidateServerStartPolicy()
+	if err != nil {
+		glog.Errorf("Invalid server start policy for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.Spec.Shutdown.Validate()
+	if err != nil {
+		glog.Errorf("Invalid shutdown options for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.ValidateScaleDownPolicy()
+	if err != nil {
+		glog.Errorf("Invalid scale down policy for server %s: %s", server.Name, err)
+		return err
+	}
+
+	err = server.Spec.RestartPolicy.Validate()
+	if err != nil {
+		glog.Errorf("Invalid restart policy for server %s: %s", server.Name, err)
+		return err
+	}
+
 	// Find Service and if it does not exist create it
 	existingService, err := GetServiceForWebLogicManagedServer(server, kubeClient)
 	if err != nil {
@@ -221,6 +313,16 @@ func updateWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient
 		return err
 	}
 
+	_, err = CreateOrUpdateHorizontalPodAutoscalerForWebLogicManagedServer(kubeClient, server)
+	if err != nil {
+		return err
+	}
+
+	err = CreateOrUpdatePodDisruptionBudgetForWebLogicManagedServer(kubeClient, server)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -234,6 +336,17 @@ func updateWebLogicManagedServerLabel(server *types.WebLogicManagedServer, restC
 	return result.Error()
 }
 
+// putWebLogicManagedServer writes the given server, spec and status, through the API.
+func putWebLogicManagedServer(server *types.WebLogicManagedServer, restClient *rest.RESTClient) error {
+	return restClient.Put().
+		Resource(constants.WebLogicManagedServerResourceKindPlural).
+		Namespace(server.Namespace).
+		Name(server.Name).
+		Body(server).
+		Do().
+		Error()
+}
+
 // When delete server is called we will delete the stateful set (which also deletes the associated service)
 //TODO handling to call stopWeblogic.sh needs to be done here
 func deleteWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient kubernetes.Interface, restClient *rest.RESTClient) error {
@@ -252,6 +365,11 @@ func deleteWebLogicManagedServer(server *types.WebLogicManagedServer, kubeClient
 		return err
 	}
 
+	err = DeletePodDisruptionBudgetForWebLogicManagedServer(kubeClient, server)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -325,11 +443,12 @@ func updateServerWithReplicaSet(server *types.WebLogicManagedServer, replicaSet
 	// Check how a rolling upgrade effects this
 	// check version of each pod
 
-	return nil
+	// Pods claim a WebLogic server when they start, label them with it
+	return LabelServerPodsForWebLogicDomain(kubeClient, server.Namespace, server.Spec.DomainName)
 }
 
-func GetServerForHorizontalPodAutoscaler(horizontalPodAutoscaler *autoscalingv1.HorizontalPodAutoscaler, restClient *rest.RESTClient) (server *types.WebLogicManagedServer, err error) {
-	if weblogicServerName, ok := horizontalPodAutoscaler.Labels[constants.HorizontalPodAutoscalerTargetLabel]; ok {
+func GetServerForHorizontalPodAutoscaler(horizontalPodAutoscaler *v2alpha1.HorizontalPodAutoscaler, restClient *rest.RESTClient) (server *types.WebLogicManagedServer, err error) {
+	if weblogicServerName, ok := horizontalPodAutoscaler.Labels[constants.WebLogicManagedServerLabel]; ok {
 		server = &types.WebLogicManagedServer{}
 		result := restClient.Get().
 			Resource(constants.WebLogicManagedServerResourceKindPlural).
@@ -339,10 +458,10 @@ func GetServerForHorizontalPodAutoscaler(horizontalPodAutoscaler *autoscalingv1.
 			Into(server)
 		return server, result
 	}
-	return nil, fmt.Errorf("unable to get Label %s from horizontalPodAutoscaler. Not part of server", constants.HorizontalPodAutoscalerTargetLabel)
+	return nil, fmt.Errorf("unable to get Label %s from horizontalPodAutoscaler. Not part of server", constants.WebLogicManagedServerLabel)
 }
 
-func updateServerWithHorizontalPodAutoscaler(server *types.WebLogicManagedServer, horizontalPodAutoscaler *autoscalingv1.HorizontalPodAutoscaler, kubeClient kubernetes.Interface, restClient *rest.RESTClient) (err error) {
+func updateServerWithHorizontalPodAutoscaler(server *types.WebLogicManagedServer, horizontalPodAutoscaler *v2alpha1.HorizontalPodAutoscaler, kubeClient kubernetes.Interface, restClient *rest.RESTClient) (err error) {
 	// Some simple logic for the time being.
 	// To add
 	// connection to the server
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/resources/services"
+	"weblogic-operator/pkg/types"
+	"weblogic-operator/pkg/wlsrest"
+)
+
+// serverNamesFromAdminServer maps the pods of running managed servers to
+// their server names by the addresses the servers listen on, as reported by
+// the admin server.
+func serverNamesFromAdminServer(clientset kubernetes.Interface, namespace, domainName string, pods []v1.Pod) (map[string]string, error) {
+	domain := &types.WebLogicDomain{}
+	err := types.DomainRESTClient.Get().
+		Resource(constants.WebLogicDomainResourceKindPlural).
+		Namespace(namespace).
+		Name(domainName).
+		Do().
+		Into(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := wlsrest.NewClientForDomain(clientset, domain)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := client.ServerAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	serverNames := map[string]string{}
+	for serverName, address := range addresses {
+		if serverName == services.AdminServerName {
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Status.PodIP == address {
+				serverNames[pod.Name] = serverName
+			}
+		}
+	}
+	return serverNames, nil
+}
+
+// LabelServerPodsForWebLogicDomain labels each managed server pod of a domain
+// with the name of the WebLogic server it runs, so the per-server Services
+// select it. The servers are matched to the pods by the addresses the admin
+// server reports for them. It only knows running servers, so labels of pods
+// not found there are kept.
+func LabelServerPodsForWebLogicDomain(clientset kubernetes.Interface, namespace, domainName string) error {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=managedserver", domainName)}
+	pods, err := clientset.CoreV1().Pods(namespace).List(opts)
+	if err != nil {
+		glog.Errorf("Unable to list managed server pods for %s: %s", domainName, err)
+		return err
+	}
+
+	serverNames, err := serverNamesFromAdminServer(clientset, namespace, domainName, pods.Items)
+	if err != nil {
+		glog.V(4).Infof("Unable to read server addresses of domain %s: %s", domainName, err)
+		return nil
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		serverName, found := serverNames[pod.Name]
+		if !found || pod.Labels[constants.WebLogicServerNameLabel] == serverName {
+			continue
+		}
+
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[constants.WebLogicServerNameLabel] = serverName
+
+		glog.V(4).Infof("Labelling pod %s with server %q", pod.Name, serverName)
+		_, err = clientset.CoreV1().Pods(namespace).Update(pod)
+		if err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateOrUpdateService creates the given Service, or brings the type, ports
+// and selector of an existing one in line with it. Node ports already
+// allocated to a port are kept.
+func CreateOrUpdateService(clientset kubernetes.Interface, svc *v1.Service) error {
+	client := clientset.CoreV1().Services(svc.Namespace)
+
+	existing, err := client.Get(svc.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		glog.V(4).Infof("Creating service %s", svc.Name)
+		_, err = client.Create(svc)
+		return err
+	}
+	if err != nil {
+		glog.Errorf("Unable to get service %s: %s", svc.Name, err)
+		return err
+	}
+
+	updated := *existing
+	updated.Spec.Type = svc.Spec.Type
+	updated.Spec.Selector = svc.Spec.Selector
+	updated.Spec.Ports = make([]v1.ServicePort, len(svc.Spec.Ports))
+	for i, port := range svc.Spec.Ports {
+		if svc.Spec.Type != v1.ServiceTypeClusterIP {
+			for _, existingPort := range existing.Spec.Ports {
+				if existingPort.Name == port.Name {
+					port.NodePort = existingPort.NodePort
+				}
+			}
+		}
+		// Mirror the defaulting of the API server so unchanged ports compare equal
+		if port.TargetPort == (intstr.IntOrString{}) {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+		updated.Spec.Ports[i] = port
+	}
+
+	if reflect.DeepEqual(existing.Spec, updated.Spec) {
+		return nil
+	}
+
+	glog.V(2).Infof("Updating service %s", svc.Name)
+	_, err = client.Update(&updated)
+	return err
+}
+
+// DeleteService deletes the named Service, if any.
+func DeleteService(clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.CoreV1().Services(namespace).Delete(name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		glog.Errorf("Could not delete service %s: %s", name, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package types
+
+// AdminSecretName returns the name of the Secret holding the username and
+// password of the administrator of the domain.
+func (c *WebLogicDomain) AdminSecretName() string {
+	if c.Spec.AdminSecret != "" {
+		return c.Spec.AdminSecret
+	}
+	return c.Name + "-admin-credentials"
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"k8s.io/api/autoscaling/v2alpha1"
+)
+
+// ServerAutoscaling adds a HorizontalPodAutoscaler to a managed server set.
+// Without settings the set scales between one server and the managed server
+// count of the domain on 50% CPU utilization. The autoscaler owns the number
+// of servers; serversToRun only raises its minimum. Sets without autoscaling
+// run serversToRun servers.
+type ServerAutoscaling struct {
+	// Enabled turns the autoscaler off while keeping its settings when false.
+	// Defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// MinReplicas is the lower bound of the number of servers. Defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper bound of the number of servers. Defaults to the managed
+	// server count of the domain.
+	// +optional
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+	// Metrics the autoscaler scales on, e.g. CPU and memory utilization or
+	// WebLogic metrics such as open sessions published through a custom
+	// metrics adapter. Defaults to 50% CPU utilization.
+	// +optional
+	Metrics []v2alpha1.MetricSpec `json:"metrics,omitempty"`
+}
+
+// AutoscalingEnabled returns true if the server set is scaled by a
+// HorizontalPodAutoscaler, which takes an autoscaling section.
+func (c *WebLogicManagedServer) AutoscalingEnabled() bool {
+	return c.Spec.Autoscaling != nil && (c.Spec.Autoscaling.Enabled == nil || *c.Spec.Autoscaling.Enabled)
+}
+
+// ScalingBounds returns the lower and upper bound of serversToRun. The upper
+// bound defaults to the managed server count of the domain, so the domain
+// must have been populated.
+func (c *WebLogicManagedServer) ScalingBounds() (int32, int32) {
+	var minReplicas int32 = 1
+	var maxReplicas int32 = int32(c.Spec.Domain.Spec.ManagedServerCount)
+	if settings := c.Spec.Autoscaling; settings != nil {
+		if settings.MinReplicas != nil {
+			minReplicas = *settings.MinReplicas
+		}
+		if settings.MaxReplicas != 0 {
+			maxReplicas = settings.MaxReplicas
+		}
+	}
+	return minReplicas, maxReplicas
+}
+
+// AutoscalerReplicas returns the minReplicas and maxReplicas of the
+// HorizontalPodAutoscaler of the set. The desired servers to run raise the
+// lower bound, up to the upper one. The domain must have been populated.
+func (c *WebLogicManagedServer) AutoscalerReplicas() (int32, int32) {
+	minReplicas, maxReplicas := c.ScalingBounds()
+	if desired := c.DesiredServersToRun(); desired > minReplicas {
+		minReplicas = desired
+	}
+	if minReplicas > maxReplicas {
+		minReplicas = maxReplicas
+	}
+	return minReplicas, maxReplicas
+}
+
+// ReplicaSetReplicas returns the replicas of the ReplicaSet of the set given
+// its current ones, nil when it is created. These are the desired servers to
+// run unless a HorizontalPodAutoscaler owns them, in which case the current
+// replicas are kept within the bounds of the autoscaler. It does not scale a
+// ReplicaSet from zero. The domain must have been populated.
+func (c *WebLogicManagedServer) ReplicaSetReplicas(current *int32) int32 {
+	if !c.AutoscalingEnabled() || !c.ServersStarted() {
+		return c.DesiredServersToRun()
+	}
+
+	minReplicas, maxReplicas := c.AutoscalerReplicas()
+	if current == nil || *current < minReplicas {
+		return minReplicas
+	}
+	if *current > maxReplicas {
+		return maxReplicas
+	}
+	return *current
+}
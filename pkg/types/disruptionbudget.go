@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DisruptionBudget limits how many server pods voluntary disruptions such as
+// node drains may evict at once. At most one of the fields may be set; when
+// neither is, one pod at a time may be unavailable.
+type DisruptionBudget struct {
+	// MinAvailable is the number or percentage of pods that must stay available.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or percentage of pods that may be unavailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// Validate returns an error if the budget sets both of its fields.
+func (b *DisruptionBudget) Validate() error {
+	if b != nil && b.MinAvailable != nil && b.MaxUnavailable != nil {
+		return fmt.Errorf("only one of minAvailable and maxUnavailable may be set")
+	}
+	return nil
+}
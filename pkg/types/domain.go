@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,26 +35,128 @@ type WebLogicDomainSpec struct {
 	ManagedServerCount int      `json:"managedServerCount"`
 	ServersAvailable   []Server `json:"serversAvailable"`
 	ServersRunning     []Server `json:"serversRunning"`
-	// Replicas defines the number of running Weblogic server instances
-	Replicas int32 `json:"replicas,omitempty"`
+	// Replicas defines the number of running admin server instances, 0 or 1.
+	// 0 stops the admin server. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// ServerStartPolicy stops the whole domain (NEVER) or its managed servers
+	// (ADMIN_ONLY) while keeping its storage. Defaults to ALWAYS.
+	// +optional
+	ServerStartPolicy ServerStartPolicy `json:"serverStartPolicy,omitempty"`
+	// Shutdown configures how the admin server is shut down.
+	// +optional
+	Shutdown *ShutdownOptions `json:"shutdown,omitempty"`
+	// HealthCheck restarts servers that do not reach RUNNING.
+	// +optional
+	HealthCheck *ServerHealthCheck `json:"healthCheck,omitempty"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node.
 	// Selector which must match a node's labels for the pod to be scheduled on that node.
 	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Ports declares the listen ports and channels of the domain's servers.
+	// +optional
+	Ports WebLogicDomainPorts `json:"ports,omitempty"`
+	// TLS enables SSL on the servers and t3s between them.
+	// +optional
+	TLS *WebLogicDomainTLS `json:"tls,omitempty"`
+	// AdminSecret names a Secret holding the username and password of the
+	// domain administrator. When empty the operator generates the Secret
+	// <domain>-admin-credentials for the user weblogic. Domains created with
+	// the former fixed credentials must name a Secret holding these.
+	// +optional
+	AdminSecret string `json:"adminSecret,omitempty"`
+	// NodeManager starts the managed servers through a Node Manager in their
+	// pods, letting WebLogic restart them without replacing the pods.
+	// +optional
+	NodeManager *WebLogicDomainNodeManager `json:"nodeManager,omitempty"`
+	// LogExport adds a sidecar to the server pods printing their logs to
+	// stdout as JSON records.
+	// +optional
+	LogExport *WebLogicDomainLogExport `json:"logExport,omitempty"`
+	// Services selects the types of the per-server and cluster Services.
+	// +optional
+	Services WebLogicDomainServices `json:"services,omitempty"`
+	// Ingress routes external HTTP traffic to the domain.
+	// +optional
+	Ingress *WebLogicDomainIngress `json:"ingress,omitempty"`
+	// NetworkPolicy isolates the pods of the domain when enabled.
+	// +optional
+	NetworkPolicy *WebLogicDomainNetworkPolicy `json:"networkPolicy,omitempty"`
+	// Affinity constrains the nodes the admin server pod is scheduled on. When
+	// unset the admin server prefers nodes not running the domain's managed servers.
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// Tolerations let the admin server pod schedule onto tainted nodes.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// DomainModel declares datasources, JMS resources, work managers and
+	// application deployments to configure in the domain. Resources removed
+	// from the model are deleted from the domain.
+	// +optional
+	DomainModel *WebLogicDomainModel `json:"domainModel,omitempty"`
+	// DomainModelConfigMap names a ConfigMap holding the domain model as YAML
+	// under the domainModel.yaml key. It is mutually exclusive with DomainModel.
+	// +optional
+	DomainModelConfigMap string `json:"domainModelConfigMap,omitempty"`
+	// ConfigOverrides names a ConfigMap of per-environment overrides mounted into
+	// every server pod. XML files are applied as situational configuration and
+	// YAML files are merged into the domain model by resource name. Changing the
+	// ConfigMap restarts the servers of the domain.
+	// +optional
+	ConfigOverrides string `json:"configOverrides,omitempty"`
+	// ReadinessProbe tunes the probe reporting the admin server ready once it is RUNNING.
+	// +optional
+	ReadinessProbe *ProbeSettings `json:"readinessProbe,omitempty"`
+	// LivenessProbe tunes the probe restarting the admin server pod when the server has failed.
+	// +optional
+	LivenessProbe *ProbeSettings `json:"livenessProbe,omitempty"`
+	// Compute Resources required by the admin server container.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// JavaOptions are passed to the admin server JVM through JAVA_OPTIONS.
+	// +optional
+	JavaOptions string `json:"javaOptions,omitempty"`
+	// UserMemArgs overrides the JVM memory arguments of the admin server.
+	// When empty they are derived from the memory limit and HeapPercentage.
+	// +optional
+	UserMemArgs string `json:"userMemArgs,omitempty"`
+	// HeapPercentage is the share of the memory limit given to the Java heap.
+	// Defaults to 75.
+	// +optional
+	HeapPercentage int32 `json:"heapPercentage,omitempty"`
+	// DisruptionBudget limits voluntary disruptions of the admin server pod.
+	// By default the admin server may be evicted.
+	// +optional
+	DisruptionBudget *DisruptionBudget `json:"disruptionBudget,omitempty"`
+	// ServerPod adds sidecars, init containers, volumes, environment
+	// variables, annotations and labels to the admin server pod.
+	ServerPod `json:",inline"`
+}
+
+// WebLogicDomainStatus holds the state the operator records for a domain
+type WebLogicDomainStatus struct {
+	// ConfigOverridesHash is the digest of the config overrides the servers
+	// are expected to run with.
+	ConfigOverridesHash string `json:"configOverridesHash,omitempty"`
+	// Servers are the states of the servers running in pods, as last
+	// reported by the admin server.
+	Servers []ServerStatus `json:"servers,omitempty"`
 }
 
 // WebLogicDomain represents a doamin spec and associated metadata
 type WebLogicDomain struct {
-	metav1.TypeMeta         `json:",inline"`
-	metav1.ObjectMeta       `json:"metadata"`
-	Spec WebLogicDomainSpec `json:"spec"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              WebLogicDomainSpec   `json:"spec"`
+	Status            WebLogicDomainStatus `json:"status,omitempty"`
 }
 
 type WebLogicDomainList struct {
-	metav1.TypeMeta        `json:",inline"`
-	metav1.ListMeta        `json:"metadata"`
-	Items []WebLogicDomain `json:"items"`
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []WebLogicDomain `json:"items"`
 }
 
 // EnsureDefaults will ensure that if a user omits and fields in the
@@ -63,8 +168,10 @@ func (c *WebLogicDomain) EnsureDefaults() *WebLogicDomain {
 		c.Spec.ManagedServerCount = defaultDomainManagedServerCount
 	}
 
-	if c.Spec.Replicas == 0 || c.Spec.Replicas > 1 {
-		c.Spec.Replicas = defaultDomainReplicas
+	// 0 means stopped, only an omitted or too large count is replaced
+	if c.Spec.Replicas == nil || *c.Spec.Replicas > 1 {
+		replicas := int32(defaultDomainReplicas)
+		c.Spec.Replicas = &replicas
 	}
 
 	if c.Spec.Version == "" {
@@ -74,6 +181,15 @@ func (c *WebLogicDomain) EnsureDefaults() *WebLogicDomain {
 	return c
 }
 
+// ManagedServerNames returns the names of the managed servers created in the domain.
+func (c *WebLogicDomain) ManagedServerNames() []string {
+	names := make([]string, c.Spec.ManagedServerCount)
+	for i := range names {
+		names[i] = fmt.Sprintf("managedserver-%d", i)
+	}
+	return names
+}
+
 func (c *WebLogicDomain) GetObjectKind() schema.ObjectKind {
 	return &c.TypeMeta
 }
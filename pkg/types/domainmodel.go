@@ -0,0 +1,87 @@
+package types
+
+import (
+	"k8s.io/api/core/v1"
+)
+
+// WebLogicDomainModel declares the resources that are configured in a domain
+// in addition to its servers. The operator translates the model into a WLST
+// script that is applied to the domain before the admin server starts.
+type WebLogicDomainModel struct {
+	DataSources    []DataSource    `json:"dataSources,omitempty"`
+	JMSServers     []JMSServer     `json:"jmsServers,omitempty"`
+	JMSModules     []JMSModule     `json:"jmsModules,omitempty"`
+	WorkManagers   []WorkManager   `json:"workManagers,omitempty"`
+	AppDeployments []AppDeployment `json:"appDeployments,omitempty"`
+}
+
+// DataSource describes a generic JDBC system resource.
+type DataSource struct {
+	Name       string `json:"name"`
+	JNDIName   string `json:"jndiName"`
+	URL        string `json:"url"`
+	DriverName string `json:"driverName"`
+	User       string `json:"user,omitempty"`
+	// PasswordSecret selects the key of a Secret holding the database password.
+	// +optional
+	PasswordSecret  *v1.SecretKeySelector `json:"passwordSecret,omitempty"`
+	InitialCapacity int32                 `json:"initialCapacity,omitempty"`
+	MaxCapacity     int32                 `json:"maxCapacity,omitempty"`
+	TestTableName   string                `json:"testTableName,omitempty"`
+	// Targets defaults to every managed server of the domain.
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+}
+
+// JMSServer describes a JMS server hosted by a single WebLogic server.
+type JMSServer struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+// JMSModule describes a JMS system module and the destinations it contains.
+type JMSModule struct {
+	Name string `json:"name"`
+	// Targets defaults to every managed server of the domain.
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+	// JMSServers the module's destinations are deployed to.
+	JMSServers          []string               `json:"jmsServers,omitempty"`
+	ConnectionFactories []JMSConnectionFactory `json:"connectionFactories,omitempty"`
+	Queues              []JMSDestination       `json:"queues,omitempty"`
+	Topics              []JMSDestination       `json:"topics,omitempty"`
+}
+
+type JMSConnectionFactory struct {
+	Name     string `json:"name"`
+	JNDIName string `json:"jndiName"`
+}
+
+type JMSDestination struct {
+	Name     string `json:"name"`
+	JNDIName string `json:"jndiName"`
+}
+
+// WorkManager describes a self-tuning work manager and its thread constraints.
+type WorkManager struct {
+	Name string `json:"name"`
+	// Targets defaults to every managed server of the domain.
+	// +optional
+	Targets    []string `json:"targets,omitempty"`
+	MinThreads int32    `json:"minThreads,omitempty"`
+	MaxThreads int32    `json:"maxThreads,omitempty"`
+}
+
+// AppDeployment describes an application archive available on the domain storage.
+type AppDeployment struct {
+	Name       string `json:"name"`
+	SourcePath string `json:"sourcePath"`
+	ModuleType string `json:"moduleType,omitempty"`
+	// StagingMode is one of stage, nostage or external_stage. Defaults to nostage
+	// since every server shares the domain storage.
+	// +optional
+	StagingMode string `json:"stagingMode,omitempty"`
+	// Targets defaults to every managed server of the domain.
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+}
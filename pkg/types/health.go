@@ -0,0 +1,64 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultStuckServerGracePeriodSeconds = 300
+
+// ServerHealthCheck configures how the operator treats servers of a domain
+// that do not reach RUNNING.
+type ServerHealthCheck struct {
+	// RestartStuckServers deletes the pod of a server stuck in ADMIN, FAILED
+	// or UNKNOWN for longer than the grace period, so it is started again.
+	// +optional
+	RestartStuckServers bool `json:"restartStuckServers,omitempty"`
+	// StuckServerGracePeriodSeconds is how long a server may stay in ADMIN,
+	// FAILED or UNKNOWN before it is considered stuck. Defaults to 300.
+	// +optional
+	StuckServerGracePeriodSeconds *int64 `json:"stuckServerGracePeriodSeconds,omitempty"`
+}
+
+// RestartsStuckServers returns true if the pods of stuck servers are restarted.
+func (h *ServerHealthCheck) RestartsStuckServers() bool {
+	return h != nil && h.RestartStuckServers
+}
+
+// StuckServerGracePeriod returns how long a server may stay in a stuck state.
+func (h *ServerHealthCheck) StuckServerGracePeriod() time.Duration {
+	if h == nil || h.StuckServerGracePeriodSeconds == nil {
+		return defaultStuckServerGracePeriodSeconds * time.Second
+	}
+	return time.Duration(*h.StuckServerGracePeriodSeconds) * time.Second
+}
+
+// ServerStatus is the state of a server as reported by the admin server.
+type ServerStatus struct {
+	Name string `json:"name"`
+	// Pod is the pod the server runs in.
+	Pod string `json:"pod,omitempty"`
+	// State is the life cycle state of the server, e.g. RUNNING.
+	State string `json:"state"`
+	// Health is the overall health of a running server, e.g. HEALTH_OK.
+	Health string `json:"health,omitempty"`
+	// Since is when the server entered its state.
+	Since metav1.Time `json:"since"`
+	// Stuck is set once the server stayed in ADMIN, FAILED or UNKNOWN for
+	// longer than the grace period.
+	Stuck bool `json:"stuck,omitempty"`
+}
+
+// IsStuckServerState returns true if a server in the given state does not
+// serve requests and will not recover without intervention.
+func IsStuckServerState(state string) bool {
+	return state == "ADMIN" || state == "UNKNOWN" || IsFailedServerState(state)
+}
+
+// IsFailedServerState returns true for FAILED and the states derived from it,
+// like FAILED_NOT_RESTARTABLE.
+func IsFailedServerState(state string) bool {
+	return strings.HasPrefix(state, "FAILED")
+}
@@ -0,0 +1,36 @@
+package types
+
+// WebLogicDomainIngress routes external HTTP traffic to the managed servers of
+// a domain through the cluster Service, and optionally to the admin console.
+type WebLogicDomainIngress struct {
+	// Hosts the applications are served on. All hosts are matched when empty.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+	// Path routed to the managed servers. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// TLSSecret names a Secret holding the TLS certificate for the hosts.
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
+	// Class selects the ingress controller through the kubernetes.io/ingress.class annotation.
+	// +optional
+	Class string `json:"class,omitempty"`
+	// Annotations are added to the generated Ingresses, e.g. to configure the controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// AdminConsole routes the admin console of the admin server. Off by default.
+	// +optional
+	AdminConsole *AdminConsoleIngress `json:"adminConsole,omitempty"`
+}
+
+// AdminConsoleIngress routes the admin console through its own Ingress.
+type AdminConsoleIngress struct {
+	// Enabled turns on the admin console route.
+	Enabled bool `json:"enabled"`
+	// Hosts the console is served on. Defaults to the hosts of the applications.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+	// Path routed to the admin server. Defaults to /console.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"k8s.io/api/core/v1"
+)
+
+// WebLogicDomainLogExport writes the server, access and domain logs of the
+// server pods to an emptyDir, from which a sidecar prints them to stdout as
+// JSON records for the log pipeline of the cluster.
+type WebLogicDomainLogExport struct {
+	// Image runs the log exporter sidecar. Defaults to the logExporterImage of
+	// the operator configuration.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Resources of the log exporter sidecar.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// LogExportEnabled returns true if the logs of the servers are exported by a
+// sidecar.
+func (c *WebLogicDomain) LogExportEnabled() bool {
+	return c.Spec.LogExport != nil
+}
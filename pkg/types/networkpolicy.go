@@ -0,0 +1,36 @@
+package types
+
+import (
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// WebLogicDomainNetworkPolicy isolates the pods of a domain. Once enabled,
+// the servers only accept traffic from each other, the ingress controllers on
+// the managed server ports and the operator on the admin port.
+type WebLogicDomainNetworkPolicy struct {
+	// Enabled turns on the generated NetworkPolicies.
+	Enabled bool `json:"enabled"`
+	// IngressControllers select the pods allowed to reach the managed server
+	// ports. No external traffic reaches the managed servers when empty.
+	// +optional
+	IngressControllers []networkingv1.NetworkPolicyPeer `json:"ingressControllers,omitempty"`
+	// Operator selects the operator pods allowed to reach the admin server.
+	// The operator usually runs in a namespace of its own, so this is
+	// typically a namespaceSelector matching a label of that namespace.
+	Operator []networkingv1.NetworkPolicyPeer `json:"operator"`
+}
+
+// ValidateNetworkPolicy returns an error if isolation is enabled without
+// selecting the operator, which would cut it off from the admin server.
+func (c *WebLogicDomain) ValidateNetworkPolicy() error {
+	settings := c.Spec.NetworkPolicy
+	if settings == nil || !settings.Enabled {
+		return nil
+	}
+	if len(settings.Operator) == 0 {
+		return fmt.Errorf("networkPolicy.operator must select the operator pods, e.g. with a namespaceSelector of the operator's namespace")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"fmt"
+)
+
+const defaultNodeManagerPort = 5556
+
+// WebLogicDomainNodeManager runs a Node Manager in every managed server pod.
+// The managed servers are started through it, so WebLogic restarts a server
+// within its pod instead of the pod being replaced.
+type WebLogicDomainNodeManager struct {
+	// Port is the plain listen port of the Node Managers. Defaults to 5556.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// NodeManagerEnabled returns true if managed servers run under a Node Manager.
+func (c *WebLogicDomain) NodeManagerEnabled() bool {
+	return c.Spec.NodeManager != nil
+}
+
+// NodeManagerPort returns the listen port of the Node Managers, or 0 if the
+// Node Manager is not enabled.
+func (c *WebLogicDomain) NodeManagerPort() int32 {
+	if c.Spec.NodeManager == nil {
+		return 0
+	}
+	if c.Spec.NodeManager.Port == 0 {
+		return defaultNodeManagerPort
+	}
+	return c.Spec.NodeManager.Port
+}
+
+// ValidateNodeManager returns an error if the Node Manager port clashes with
+// a port of the servers.
+func (c *WebLogicDomain) ValidateNodeManager() error {
+	port := c.NodeManagerPort()
+	if port == 0 {
+		return nil
+	}
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("nodeManager port %d is out of range", port)
+	}
+	for i := 0; i < c.Spec.ManagedServerCount; i++ {
+		if port == c.ManagedServerPort(i) || port == c.ManagedServerSSLPort(i) {
+			return fmt.Errorf("nodeManager port %d is used by managed server %d", port, i)
+		}
+	}
+	return nil
+}
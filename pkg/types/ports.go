@@ -0,0 +1,195 @@
+package types
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	defaultAdminPort             = 7001
+	defaultManagedServerBasePort = 7003
+	defaultManagedServerPortStep = 2
+	defaultClusterPort           = 8001
+)
+
+// WebLogicDomainPorts declares the ports the servers of a domain listen on.
+// They are configured when the domain is created.
+type WebLogicDomainPorts struct {
+	// AdminPort is the listen port of the admin server. Defaults to 7001.
+	// +optional
+	AdminPort int32 `json:"adminPort,omitempty"`
+	// AdminSSLPort enables SSL on the admin server on the given port.
+	// +optional
+	AdminSSLPort int32 `json:"adminSSLPort,omitempty"`
+	// ManagedServerBasePort is the listen port of the first managed server.
+	// Defaults to 7003.
+	// +optional
+	ManagedServerBasePort int32 `json:"managedServerBasePort,omitempty"`
+	// ManagedServerPortStep is added to the port of each further managed
+	// server. Defaults to 2, 0 gives all managed servers the same ports.
+	// +optional
+	ManagedServerPortStep *int32 `json:"managedServerPortStep,omitempty"`
+	// ManagedServerSSLBasePort enables SSL on the managed servers, starting
+	// at the given port and using the same step as the listen ports.
+	// +optional
+	ManagedServerSSLBasePort int32 `json:"managedServerSSLBasePort,omitempty"`
+	// ClusterPort is a port all managed servers listen on for HTTP, next to
+	// their own ports, so the cluster Service can balance across them. With
+	// SSL on the managed servers the following port serves HTTPS. Only used
+	// when the managed servers have different ports. Defaults to 8001.
+	// +optional
+	ClusterPort int32 `json:"clusterPort,omitempty"`
+	// T3Channels are additional T3 network channels of the admin server.
+	// +optional
+	T3Channels []T3Channel `json:"t3Channels,omitempty"`
+}
+
+// T3Channel is a T3 network access point of the admin server.
+type T3Channel struct {
+	// Name of the channel, which also names its container and service port.
+	// At most 15 lowercase letters, digits and hyphens, other than admin and
+	// admin-ssl.
+	Name string `json:"name"`
+	// Port the channel listens on.
+	Port int32 `json:"port"`
+	// PublicAddress is the address clients outside the cluster use.
+	// +optional
+	PublicAddress string `json:"publicAddress,omitempty"`
+	// PublicPort is the port clients outside the cluster use. Defaults to Port.
+	// +optional
+	PublicPort int32 `json:"publicPort,omitempty"`
+}
+
+// AdminPort returns the listen port of the admin server.
+func (c *WebLogicDomain) AdminPort() int32 {
+	if c.Spec.Ports.AdminPort == 0 {
+		return defaultAdminPort
+	}
+	return c.Spec.Ports.AdminPort
+}
+
+// ManagedServerBasePort returns the listen port of the first managed server.
+func (c *WebLogicDomain) ManagedServerBasePort() int32 {
+	if c.Spec.Ports.ManagedServerBasePort == 0 {
+		return defaultManagedServerBasePort
+	}
+	return c.Spec.Ports.ManagedServerBasePort
+}
+
+// ManagedServerPortStep returns the distance between the ports of consecutive managed servers.
+func (c *WebLogicDomain) ManagedServerPortStep() int32 {
+	if c.Spec.Ports.ManagedServerPortStep == nil {
+		return defaultManagedServerPortStep
+	}
+	return *c.Spec.Ports.ManagedServerPortStep
+}
+
+// ManagedServerPort returns the listen port of managed server i.
+func (c *WebLogicDomain) ManagedServerPort(i int) int32 {
+	return c.ManagedServerBasePort() + int32(i)*c.ManagedServerPortStep()
+}
+
+// ManagedServerSSLPort returns the SSL listen port of managed server i, or 0
+// if SSL is not enabled on the managed servers.
+func (c *WebLogicDomain) ManagedServerSSLPort(i int) int32 {
+	if c.Spec.Ports.ManagedServerSSLBasePort == 0 {
+		return 0
+	}
+	return c.Spec.Ports.ManagedServerSSLBasePort + int32(i)*c.ManagedServerPortStep()
+}
+
+// ClusterPort returns the HTTP port shared by all managed servers: their
+// listen port if they have the same, or else the port of the channel added
+// to each of them.
+func (c *WebLogicDomain) ClusterPort() int32 {
+	if c.UniformManagedServerPorts() {
+		return c.ManagedServerPort(0)
+	}
+	if c.Spec.Ports.ClusterPort == 0 {
+		return defaultClusterPort
+	}
+	return c.Spec.Ports.ClusterPort
+}
+
+// ClusterSSLPort returns the HTTPS port shared by all managed servers, or 0
+// if SSL is not enabled on the managed servers.
+func (c *WebLogicDomain) ClusterSSLPort() int32 {
+	if c.Spec.Ports.ManagedServerSSLBasePort == 0 {
+		return 0
+	}
+	if c.UniformManagedServerPorts() {
+		return c.ManagedServerSSLPort(0)
+	}
+	return c.ClusterPort() + 1
+}
+
+// ClusterChannelsEnabled returns true if the managed servers get channels on
+// the cluster ports because their own ports differ.
+func (c *WebLogicDomain) ClusterChannelsEnabled() bool {
+	return !c.UniformManagedServerPorts()
+}
+
+// ValidatePorts returns an error if a port is out of range or used for more
+// than one purpose within the domain.
+func (c *WebLogicDomain) ValidatePorts() error {
+	if c.ManagedServerPortStep() < 0 {
+		return fmt.Errorf("managedServerPortStep must not be negative")
+	}
+
+	used := map[int32]string{}
+	add := func(port int32, purpose string) error {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("%s %d is out of range", purpose, port)
+		}
+		if other, ok := used[port]; ok && other != purpose {
+			return fmt.Errorf("%s %d collides with the %s", purpose, port, other)
+		}
+		used[port] = purpose
+		return nil
+	}
+
+	if err := add(c.AdminPort(), "admin port"); err != nil {
+		return err
+	}
+	if c.Spec.Ports.AdminSSLPort != 0 {
+		if err := add(c.Spec.Ports.AdminSSLPort, "admin SSL port"); err != nil {
+			return err
+		}
+	}
+	// Channel names also name the container and Service ports of the admin server
+	names := map[string]bool{"admin": true, "admin-ssl": true}
+	for _, channel := range c.Spec.Ports.T3Channels {
+		if errs := validation.IsValidPortName(channel.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid T3 channel name %q: %s", channel.Name, errs[0])
+		}
+		if names[channel.Name] {
+			return fmt.Errorf("T3 channel name %q is already used by another port of the admin server", channel.Name)
+		}
+		names[channel.Name] = true
+		if err := add(channel.Port, fmt.Sprintf("T3 channel %s port", channel.Name)); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < c.Spec.ManagedServerCount; i++ {
+		if err := add(c.ManagedServerPort(i), "managed server port"); err != nil {
+			return err
+		}
+		if c.Spec.Ports.ManagedServerSSLBasePort != 0 {
+			if err := add(c.ManagedServerSSLPort(i), "managed server SSL port"); err != nil {
+				return err
+			}
+		}
+	}
+	if c.ClusterChannelsEnabled() {
+		if err := add(c.ClusterPort(), "cluster port"); err != nil {
+			return err
+		}
+		if sslPort := c.ClusterSSLPort(); sslPort != 0 {
+			if err := add(sslPort, "cluster SSL port"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package types
+
+// ProbeSettings tunes the timings of a generated readiness or liveness probe.
+// Fields left at zero use the operator defaults.
+type ProbeSettings struct {
+	// Number of seconds after the container has started before the probe is initiated.
+	// +optional
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// Number of seconds after which the probe times out.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// How often (in seconds) to perform the probe.
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// Consecutive failures for the probe to be considered failed.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
@@ -65,7 +65,8 @@ func defaultWebLogicDomainList(obj interface{}) {
 func defaultWebLogicDomain(obj interface{}) {
 	domain := obj.(*WebLogicDomain)
 	domain.Spec.ManagedServerCount = defaultDomainManagedServerCount
-	domain.Spec.Replicas = defaultDomainReplicas
+	replicas := int32(defaultDomainReplicas)
+	domain.Spec.Replicas = &replicas
 	domain.Spec.Version = defaultDomainVersion
 }
 
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultMaxRestarts           = 3
+	defaultRestartWindowSeconds  = 3600
+	defaultRestartBackoffSeconds = 30
+)
+
+// ServerRestartPolicy restarts managed servers that entered FAILED while their
+// JVM keeps the pod alive, within a restart budget.
+type ServerRestartPolicy struct {
+	// MaxRestarts is the number of restarts of a server allowed within the
+	// window. Defaults to 3.
+	// +optional
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+	// WindowSeconds is the period restarts are counted over. Defaults to 3600.
+	// +optional
+	WindowSeconds *int64 `json:"windowSeconds,omitempty"`
+	// BackoffSeconds is the delay after a restart before the server is
+	// restarted again, doubled with every restart in the window. Defaults to 30.
+	// +optional
+	BackoffSeconds *int64 `json:"backoffSeconds,omitempty"`
+}
+
+// ServerRestartStatus tracks the restarts of a failed managed server.
+type ServerRestartStatus struct {
+	Name string `json:"name"`
+	// RestartCount is the number of times the operator restarted the server.
+	RestartCount int32 `json:"restartCount"`
+	// RecentRestarts are the restarts within the window.
+	RecentRestarts []metav1.Time `json:"recentRestarts,omitempty"`
+	// BudgetExhausted is set while no more restarts are allowed in the window.
+	BudgetExhausted bool `json:"budgetExhausted,omitempty"`
+	// RestartInProgress is set while the operator restarts the server.
+	RestartInProgress bool `json:"restartInProgress,omitempty"`
+}
+
+// Validate returns an error if the restart policy has negative settings.
+func (p *ServerRestartPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxRestarts != nil && *p.MaxRestarts < 0 {
+		return fmt.Errorf("restartPolicy maxRestarts must not be negative")
+	}
+	if p.WindowSeconds != nil && *p.WindowSeconds <= 0 {
+		return fmt.Errorf("restartPolicy windowSeconds must be positive")
+	}
+	if p.BackoffSeconds != nil && *p.BackoffSeconds < 0 {
+		return fmt.Errorf("restartPolicy backoffSeconds must not be negative")
+	}
+	return nil
+}
+
+// MaxRestartsInWindow returns the number of restarts allowed within the window.
+func (p *ServerRestartPolicy) MaxRestartsInWindow() int {
+	if p.MaxRestarts == nil {
+		return defaultMaxRestarts
+	}
+	return int(*p.MaxRestarts)
+}
+
+// Window returns the period restarts are counted over.
+func (p *ServerRestartPolicy) Window() time.Duration {
+	if p.WindowSeconds == nil {
+		return defaultRestartWindowSeconds * time.Second
+	}
+	return time.Duration(*p.WindowSeconds) * time.Second
+}
+
+// Backoff returns the delay before restarting a server that was restarted
+// the given number of times within the window.
+func (p *ServerRestartPolicy) Backoff(recentRestarts int) time.Duration {
+	backoff := time.Duration(defaultRestartBackoffSeconds) * time.Second
+	if p.BackoffSeconds != nil {
+		backoff = time.Duration(*p.BackoffSeconds) * time.Second
+	}
+	for i := 1; i < recentRestarts; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
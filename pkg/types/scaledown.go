@@ -0,0 +1,43 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ScaleDownPolicy selects the managed servers removed when a set scales down.
+type ScaleDownPolicy string
+
+const (
+	// ScaleDownHighestNumbered removes the servers with the highest numbers,
+	// keeping the numbering of the running servers contiguous. The default.
+	ScaleDownHighestNumbered ScaleDownPolicy = "HighestNumbered"
+	// ScaleDownLeastSessions removes the servers with the fewest open HTTP
+	// sessions.
+	ScaleDownLeastSessions ScaleDownPolicy = "LeastSessions"
+)
+
+// ValidateScaleDownPolicy returns an error if the server set's scaleDownPolicy is unknown.
+func (c *WebLogicManagedServer) ValidateScaleDownPolicy() error {
+	switch c.Spec.ScaleDownPolicy {
+	case "", ScaleDownHighestNumbered, ScaleDownLeastSessions:
+		return nil
+	}
+	return fmt.Errorf("scaleDownPolicy must be %s or %s, not %q",
+		ScaleDownHighestNumbered, ScaleDownLeastSessions, c.Spec.ScaleDownPolicy)
+}
+
+// ManagedServerNumber returns the number of a managed server named like
+// managedserver-N, or -1 for other names.
+func ManagedServerNumber(serverName string) int {
+	i := strings.LastIndex(serverName, "-")
+	if i < 0 {
+		return -1
+	}
+	number, err := strconv.Atoi(serverName[i+1:])
+	if err != nil {
+		return -1
+	}
+	return number
+}
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"weblogic-operator/pkg/util/cron"
+)
+
+// ScalingSchedule sets serversToRun of a managed server set whenever its cron
+// expression fires, e.g. "0 8 * * 1-5" to scale up on weekday mornings.
+// Schedules are evaluated in the operator's time zone. Under autoscaling the
+// scheduled count is the minimum the autoscaler keeps running, which it
+// scales above on load up to its maxReplicas.
+type ScalingSchedule struct {
+	// Name identifies the schedule in status and events.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Schedule is a cron expression with minute, hour, day of month, month
+	// and day of week fields.
+	Schedule string `json:"schedule"`
+	// ServersToRun is the number of servers to run from when the schedule
+	// fires until the next schedule does.
+	ServersToRun int32 `json:"serversToRun"`
+}
+
+// ScheduledScaling is a scaling action taken or planned by a schedule.
+type ScheduledScaling struct {
+	Schedule     string      `json:"schedule"`
+	ServersToRun int32       `json:"serversToRun"`
+	Time         metav1.Time `json:"time"`
+}
+
+// WebLogicManagedServerStatus holds the state the operator records for a
+// managed server set
+type WebLogicManagedServerStatus struct {
+	// ScheduledServersToRun replaces serversToRun since the last schedule fired.
+	ScheduledServersToRun *int32 `json:"scheduledServersToRun,omitempty"`
+	// LastScheduledScaling is the last schedule that fired.
+	LastScheduledScaling *ScheduledScaling `json:"lastScheduledScaling,omitempty"`
+	// NextScheduledScaling is the next schedule to fire.
+	NextScheduledScaling *ScheduledScaling `json:"nextScheduledScaling,omitempty"`
+	// Restarts tracks the restarts of failed servers under the restart policy.
+	Restarts []ServerRestartStatus `json:"restarts,omitempty"`
+}
+
+// ScheduleName returns the name of the schedule, defaulting to its expression.
+func (s ScalingSchedule) ScheduleName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Schedule
+}
+
+// ValidateSchedules returns an error if a schedule cannot be parsed.
+func (c *WebLogicManagedServer) ValidateSchedules() error {
+	for _, schedule := range c.Spec.Schedules {
+		if _, err := cron.Parse(schedule.Schedule); err != nil {
+			return fmt.Errorf("schedule %s: %v", schedule.ScheduleName(), err)
+		}
+		if schedule.ServersToRun < 0 {
+			return fmt.Errorf("schedule %s: serversToRun must not be negative", schedule.ScheduleName())
+		}
+	}
+	return nil
+}
+
+// DesiredServersToRun returns the number of servers the set should run: none
+// when stopped by a serverStartPolicy, otherwise the count of the last schedule
+// that fired, or serversToRun without schedules, kept within the scaling
+// bounds when a schedule applies. The domain must have been populated.
+func (c *WebLogicManagedServer) DesiredServersToRun() int32 {
+	if !c.ServersStarted() {
+		return 0
+	}
+	if len(c.Spec.Schedules) == 0 || c.Status.ScheduledServersToRun == nil {
+		return c.Spec.ServersToRun
+	}
+
+	desired := *c.Status.ScheduledServersToRun
+	minReplicas, maxReplicas := c.ScalingBounds()
+	if desired < minReplicas {
+		desired = minReplicas
+	}
+	if desired > maxReplicas {
+		desired = maxReplicas
+	}
+	return desired
+}
@@ -23,26 +23,85 @@ const (
 
 // WebLogicManagedServerSpec defines the attributes a user can specify when creating a server
 type WebLogicManagedServerSpec struct {
-	DomainName   string `json:"domainName"`
-	ServersToRun int32  `json:"serversToRun,omitempty"`
+	DomainName string `json:"domainName"`
+	// ServersToRun is the number of managed servers to run, 0 runs none.
+	ServersToRun int32 `json:"serversToRun,omitempty"`
 	Domain       WebLogicDomain
+	// ServerStartPolicy set to NEVER stops the servers of the set while
+	// keeping serversToRun for when they are started again. Defaults to ALWAYS.
+	// +optional
+	ServerStartPolicy ServerStartPolicy `json:"serverStartPolicy,omitempty"`
+	// Shutdown configures how the managed servers are shut down.
+	// +optional
+	Shutdown *ShutdownOptions `json:"shutdown,omitempty"`
+	// ScaleDownPolicy selects the servers the operator suspends and shuts
+	// down when the set scales down. Defaults to HighestNumbered. When the
+	// autoscaler scales down the ReplicaSet picks the pods, whose servers are
+	// then shut down as configured by Shutdown.
+	// +optional
+	ScaleDownPolicy ScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+	// RestartPolicy lets the operator restart servers that entered FAILED,
+	// which Kubernetes does not notice while their JVM keeps running.
+	// +optional
+	RestartPolicy *ServerRestartPolicy `json:"restartPolicy,omitempty"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node.
 	// Selector which must match a node's labels for the pod to be scheduled on that node.
 	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity constrains the nodes the managed server pods are scheduled on.
+	// When unset managed servers of a domain are spread across nodes and kept
+	// off the admin server's node when possible.
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// Tolerations let the managed server pods schedule onto tainted nodes.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 	// Compute Resources required by this container.
 	// Cannot be updated.
 	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#resources
 	// +optional
 	Resources v1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,8,opt,name=resources"`
+	// ReadinessProbe tunes the probe reporting a managed server ready once it is RUNNING.
+	// +optional
+	ReadinessProbe *ProbeSettings `json:"readinessProbe,omitempty"`
+	// LivenessProbe tunes the probe restarting a managed server pod when the server has failed.
+	// +optional
+	LivenessProbe *ProbeSettings `json:"livenessProbe,omitempty"`
+	// JavaOptions are passed to the managed server JVMs through JAVA_OPTIONS.
+	// +optional
+	JavaOptions string `json:"javaOptions,omitempty"`
+	// UserMemArgs overrides the JVM memory arguments of the managed servers.
+	// When empty they are derived from the memory limit and HeapPercentage.
+	// +optional
+	UserMemArgs string `json:"userMemArgs,omitempty"`
+	// HeapPercentage is the share of the memory limit given to the Java heap.
+	// Defaults to 75.
+	// +optional
+	HeapPercentage int32 `json:"heapPercentage,omitempty"`
+	// Autoscaling adds a HorizontalPodAutoscaler adjusting the number of
+	// servers. Without it serversToRun is the number of servers.
+	// +optional
+	Autoscaling *ServerAutoscaling `json:"autoscaling,omitempty"`
+	// Schedules change the number of servers to run at set times, e.g. to
+	// run more servers during business hours.
+	// +optional
+	Schedules []ScalingSchedule `json:"schedules,omitempty"`
+	// DisruptionBudget limits voluntary disruptions of the managed server pods.
+	// By default one server pod at a time may be disrupted.
+	// +optional
+	DisruptionBudget *DisruptionBudget `json:"disruptionBudget,omitempty"`
+	// ServerPod adds sidecars, init containers, volumes, environment
+	// variables, annotations and labels to the managed server pods.
+	ServerPod `json:",inline"`
 }
 
 // WebLogicManagedServer represents a server spec and associated metadata
 type WebLogicManagedServer struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              WebLogicManagedServerSpec `json:"spec"`
+	Spec              WebLogicManagedServerSpec   `json:"spec"`
+	Status            WebLogicManagedServerStatus `json:"status,omitempty"`
 }
 
 type WebLogicManagedServerList struct {
@@ -56,28 +115,31 @@ type WebLogicManagedServerList struct {
 // For example a user can choose to omit the version
 // and number of replicas
 func (c *WebLogicManagedServer) EnsureDefaults() *WebLogicManagedServer {
-	if c.Spec.ServersToRun == 0 {
-		c.Spec.ServersToRun = defaultServersToRun
-	}
-
+	// ServersToRun is left alone, 0 stops the servers of the set
 	return c
 }
 
 func (c *WebLogicManagedServer) PopulateDomain() *WebLogicManagedServer {
+	err := c.FetchDomain()
+	if err != nil {
+		glog.Errorf("Unable to read domain %s of server %s: %s", c.Spec.DomainName, c.Name, err)
+	}
+	return c
+}
+
+// FetchDomain reads the domain of the server set into its spec, returning an
+// error if the domain cannot be read.
+func (c *WebLogicManagedServer) FetchDomain() error {
 	domain := &WebLogicDomain{}
-	result := DomainRESTClient.Get().
+	err := DomainRESTClient.Get().
 		Resource(constants.WebLogicDomainResourceKindPlural).
 		Namespace(c.Namespace).
 		Name(c.Spec.DomainName).
 		Do().
 		Into(domain)
 
-	if result != nil {
-		glog.V(4).Info("Extracted domain %s for server %s", domain.Name, c.Name)
-	}
-
 	c.Spec.Domain = *domain
-	return c
+	return err
 }
 
 func (c *WebLogicManagedServer) GetObjectKind() schema.ObjectKind {
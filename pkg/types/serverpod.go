@@ -0,0 +1,37 @@
+package types
+
+import (
+	"k8s.io/api/core/v1"
+)
+
+// ServerPod are additions to the pods of WebLogic servers, passed through to
+// the generated pod template. Names, paths, environment variables, labels and
+// annotations used by the operator cannot be overridden.
+type ServerPod struct {
+	// Sidecars are containers run next to the WebLogic server.
+	// +optional
+	Sidecars []v1.Container `json:"sidecars,omitempty"`
+	// InitContainers run before the WebLogic server is started.
+	// +optional
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+	// Volumes are added to the pod, for use by sidecars, init containers and
+	// volumeMounts.
+	// +optional
+	Volumes []v1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts are added to the WebLogic server container.
+	// +optional
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Env adds environment variables to the WebLogic server container.
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// EnvFrom adds environment variables from ConfigMaps and Secrets to the
+	// WebLogic server container. Variables set by the operator take precedence.
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+	// PodAnnotations are added to the pods.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// PodLabels are added to the pods.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+}
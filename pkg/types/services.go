@@ -0,0 +1,24 @@
+package types
+
+import (
+	"k8s.io/api/core/v1"
+)
+
+// WebLogicDomainServices configures the Services created for the servers of a
+// domain: one per WebLogic server, and one balancing HTTP traffic across the
+// ready managed servers.
+type WebLogicDomainServices struct {
+	// ServerServiceType is the type of the per-server Services. Defaults to ClusterIP.
+	// +optional
+	ServerServiceType v1.ServiceType `json:"serverServiceType,omitempty"`
+	// ClusterServiceType is the type of the cluster Service. Defaults to ClusterIP.
+	// +optional
+	ClusterServiceType v1.ServiceType `json:"clusterServiceType,omitempty"`
+}
+
+// UniformManagedServerPorts returns true if all managed servers of the domain
+// listen on the same ports, so a single Service can balance across them
+// without adding cluster channels.
+func (c *WebLogicDomain) UniformManagedServerPorts() bool {
+	return c.ManagedServerPortStep() == 0 || c.Spec.ManagedServerCount <= 1
+}
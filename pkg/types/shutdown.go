@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+)
+
+// ShutdownType selects how WebLogic servers are shut down.
+type ShutdownType string
+
+const (
+	// ShutdownGraceful lets in-flight work and, unless ignored, HTTP sessions
+	// complete before the server stops. The default.
+	ShutdownGraceful ShutdownType = "Graceful"
+	// ShutdownForced stops the server immediately.
+	ShutdownForced ShutdownType = "Forced"
+
+	defaultShutdownTimeoutSeconds = 30
+	// Time for the stop script and the JVM exit on top of the shutdown timeout
+	shutdownGracePeriodMarginSeconds = 30
+)
+
+// ShutdownOptions configure how the operator shuts WebLogic servers down,
+// when their pods are deleted and when a server set is scaled down.
+type ShutdownOptions struct {
+	// Type is Graceful or Forced. Defaults to Graceful.
+	// +optional
+	Type ShutdownType `json:"type,omitempty"`
+	// TimeoutSeconds bounds a graceful shutdown, after which the server is
+	// forced down. Defaults to 30.
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+	// IgnoreSessions shuts down without waiting for HTTP sessions to
+	// complete or be replicated.
+	// +optional
+	IgnoreSessions bool `json:"ignoreSessions,omitempty"`
+}
+
+// Validate returns an error if the options are inconsistent.
+func (s *ShutdownOptions) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.Type != "" && s.Type != ShutdownGraceful && s.Type != ShutdownForced {
+		return fmt.Errorf("shutdown type must be %s or %s, not %q", ShutdownGraceful, ShutdownForced, s.Type)
+	}
+	if s.TimeoutSeconds != nil && *s.TimeoutSeconds < 0 {
+		return fmt.Errorf("shutdown timeoutSeconds must not be negative")
+	}
+	return nil
+}
+
+// Forced returns true if servers are shut down without waiting for work to complete.
+func (s *ShutdownOptions) Forced() bool {
+	return s != nil && s.Type == ShutdownForced
+}
+
+// SessionsIgnored returns true if servers shut down without waiting for HTTP sessions.
+func (s *ShutdownOptions) SessionsIgnored() bool {
+	return s != nil && s.IgnoreSessions
+}
+
+// Timeout returns the time in seconds a graceful shutdown may take.
+func (s *ShutdownOptions) Timeout() int64 {
+	if s == nil || s.TimeoutSeconds == nil {
+		return defaultShutdownTimeoutSeconds
+	}
+	return *s.TimeoutSeconds
+}
+
+// TerminationGracePeriodSeconds returns the grace period of the server pods,
+// long enough for the shutdown to complete before the pod is killed.
+func (s *ShutdownOptions) TerminationGracePeriodSeconds() int64 {
+	if s.Forced() {
+		return shutdownGracePeriodMarginSeconds
+	}
+	return s.Timeout() + shutdownGracePeriodMarginSeconds
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"fmt"
+)
+
+// ServerStartPolicy decides which servers of a domain or managed server set
+// run. Stopping servers scales their ReplicaSets to zero and keeps the domain
+// storage, so flipping the policy back resumes them.
+type ServerStartPolicy string
+
+const (
+	// ServerStartPolicyAlways runs all servers, the default.
+	ServerStartPolicyAlways ServerStartPolicy = "ALWAYS"
+	// ServerStartPolicyAdminOnly runs the admin server but no managed servers.
+	// It only applies to domains.
+	ServerStartPolicyAdminOnly ServerStartPolicy = "ADMIN_ONLY"
+	// ServerStartPolicyNever stops all servers.
+	ServerStartPolicyNever ServerStartPolicy = "NEVER"
+)
+
+// ValidateServerStartPolicy returns an error if the domain's serverStartPolicy is unknown.
+func (c *WebLogicDomain) ValidateServerStartPolicy() error {
+	switch c.Spec.ServerStartPolicy {
+	case "", ServerStartPolicyAlways, ServerStartPolicyAdminOnly, ServerStartPolicyNever:
+		return nil
+	}
+	return fmt.Errorf("serverStartPolicy must be %s, %s or %s, not %q",
+		ServerStartPolicyAlways, ServerStartPolicyAdminOnly, ServerStartPolicyNever, c.Spec.ServerStartPolicy)
+}
+
+// AdminServerReplicas returns the number of admin server pods to run.
+func (c *WebLogicDomain) AdminServerReplicas() int32 {
+	if c.Spec.ServerStartPolicy == ServerStartPolicyNever {
+		return 0
+	}
+	if c.Spec.Replicas == nil {
+		return defaultDomainReplicas
+	}
+	return *c.Spec.Replicas
+}
+
+// ManagedServersStarted returns true if the domain lets its managed servers run.
+func (c *WebLogicDomain) ManagedServersStarted() bool {
+	return c.Spec.ServerStartPolicy == "" || c.Spec.ServerStartPolicy == ServerStartPolicyAlways
+}
+
+// ValidateServerStartPolicy returns an error if the server set's serverStartPolicy is unknown.
+func (c *WebLogicManagedServer) ValidateServerStartPolicy() error {
+	switch c.Spec.ServerStartPolicy {
+	case "", ServerStartPolicyAlways, ServerStartPolicyNever:
+		return nil
+	}
+	return fmt.Errorf("serverStartPolicy must be %s or %s, not %q",
+		ServerStartPolicyAlways, ServerStartPolicyNever, c.Spec.ServerStartPolicy)
+}
+
+// ServersStarted returns true if both the server set and its domain let the
+// managed servers run. The domain must have been populated.
+func (c *WebLogicManagedServer) ServersStarted() bool {
+	return c.Spec.ServerStartPolicy != ServerStartPolicyNever && c.Spec.Domain.ManagedServersStarted()
+}
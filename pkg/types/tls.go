@@ -0,0 +1,35 @@
+package types
+
+import (
+	"fmt"
+)
+
+// WebLogicDomainTLS enables SSL on the servers of a domain. The SSL ports are
+// taken from spec.ports, of which adminSSLPort is required.
+type WebLogicDomainTLS struct {
+	// Secret names a Secret holding tls.crt, tls.key and ca.crt, used as the
+	// identity of every server. When empty the operator generates a CA and a
+	// certificate per server into the Secret <domain>-tls.
+	// +optional
+	Secret string `json:"secret,omitempty"`
+}
+
+// TLSSecretName returns the name of the Secret holding the certificates of
+// the domain, or an empty string if TLS is not enabled.
+func (c *WebLogicDomain) TLSSecretName() string {
+	if c.Spec.TLS == nil {
+		return ""
+	}
+	if c.Spec.TLS.Secret != "" {
+		return c.Spec.TLS.Secret
+	}
+	return c.Name + "-tls"
+}
+
+// ValidateTLS returns an error if TLS is enabled without an admin SSL port.
+func (c *WebLogicDomain) ValidateTLS() error {
+	if c.Spec.TLS != nil && c.Spec.Ports.AdminSSLPort == 0 {
+		return fmt.Errorf("tls requires ports.adminSSLPort to be set")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	keySize  = 2048
+	validFor = 10 * 365 * 24 * time.Hour
+)
+
+// KeyPair is a PEM encoded certificate and its private key.
+type KeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encode(der []byte, key *rsa.PrivateKey) KeyPair {
+	return KeyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}
+}
+
+func decode(pair KeyPair) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(pair.Cert)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyBlock, _ := pem.Decode(pair.Key)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// DNSNames returns the subject alternative names of a PEM encoded certificate.
+func DNSNames(cert []byte) ([]string, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.DNSNames, nil
+}
+
+// NewCA returns a self-signed certificate authority.
+func NewCA(commonName string) (KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validFor),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return encode(der, key), nil
+}
+
+// NewServerCertificate returns a certificate for the given names signed by
+// the CA. The common name is also the first subject alternative name.
+func NewServerCertificate(ca KeyPair, commonName string, dnsNames []string) (KeyPair, error) {
+	caCert, caKey, err := decode(ca)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("invalid CA: %s", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     append([]string{commonName}, dnsNames...),
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validFor),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return encode(der, key), nil
+}
@@ -0,0 +1,182 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression with the five standard fields:
+// minute, hour, day of month, month and day of week. Fields accept *, single
+// values, ranges (1-5), steps (*/15, 0-30/10) and comma separated lists.
+// Day of week runs from 0 (Sunday) to 6, 7 is accepted for Sunday as well.
+// As in Vixie cron, a day field starting with * leaves the day unrestricted,
+// and when both day fields are restricted a day matching either one fires,
+// e.g. "0 0 1,15 * 1" fires on the 1st, the 15th and every Monday.
+type Schedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// Like cron, a day matches either restricted day field when both are set
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds     = bounds{"minute", 0, 59}
+	hourBounds       = bounds{"hour", 0, 23}
+	dayOfMonthBounds = bounds{"day of month", 1, 31}
+	monthBounds      = bounds{"month", 1, 12}
+	dayOfWeekBounds  = bounds{"day of week", 0, 7}
+)
+
+// Parse parses a cron expression.
+func Parse(expression string) (*Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, found %d", expression, len(fields))
+	}
+
+	var err error
+	s := &Schedule{
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+	if s.minutes, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.daysOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek |= 1
+	}
+	return s, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		first, last, step := b.min, b.max, 1
+
+		rangePart := part
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %s field %q", b.name, part)
+			}
+			rangePart = part[:i]
+		}
+
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if first, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in %s field %q", b.name, part)
+			}
+			last = first
+			if len(bounds) == 2 {
+				if last, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid range in %s field %q", b.name, part)
+				}
+			} else if step > 1 {
+				// 5/15 means from 5 to the end in steps of 15
+				last = b.max
+			}
+		}
+
+		if first < b.min || last > b.max || first > last {
+			return 0, fmt.Errorf("%s field %q is out of range %d-%d", b.name, part, b.min, b.max)
+		}
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// Next returns the first time after t the schedule fires, in t's location.
+// The zero time is returned if the schedule never fires, e.g. on February 30.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Every valid schedule fires within a leap year cycle
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Prev returns the last time at or before t the schedule fired, in t's
+// location. The zero time is returned if the schedule never fires.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	t = t.Truncate(time.Minute)
+	limit := t.AddDate(-5, 0, 0)
+
+	// Skip back to the last minute before the field that does not match
+	for t.After(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
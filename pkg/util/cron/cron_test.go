@@ -0,0 +1,144 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"1-b * * * *",
+	}
+	for _, expression := range tests {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", expression)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		expression string
+		after      string
+		want       string
+	}{
+		// Every minute, seconds are ignored
+		{"* * * * *", "2017-10-02 10:15", "2017-10-02 10:16"},
+		{"30 8 * * *", "2017-10-02 08:30", "2017-10-03 08:30"},
+		{"30 8 * * *", "2017-10-02 08:29", "2017-10-02 08:30"},
+
+		// Ranges, lists and steps
+		{"0 9-17 * * *", "2017-10-02 17:00", "2017-10-03 09:00"},
+		{"0 9-17 * * *", "2017-10-02 12:30", "2017-10-02 13:00"},
+		{"*/15 * * * *", "2017-10-02 10:16", "2017-10-02 10:30"},
+		{"0-30/10 * * * *", "2017-10-02 10:31", "2017-10-02 11:00"},
+		{"5/20 * * * *", "2017-10-02 10:26", "2017-10-02 10:45"},
+		{"0 6,18 * * *", "2017-10-02 07:00", "2017-10-02 18:00"},
+		{"0 0 * 2-4/2 *", "2017-10-02 00:00", "2018-02-01 00:00"},
+
+		// Only one day field restricted: both have to match
+		{"0 0 13 * *", "2017-10-02 00:00", "2017-10-13 00:00"},
+		{"0 0 * * 1-5", "2017-10-06 12:00", "2017-10-09 00:00"},
+		{"0 0 */2 * *", "2017-10-02 00:00", "2017-10-03 00:00"},
+		{"0 0 * * */3", "2017-10-02 00:00", "2017-10-04 00:00"},
+
+		// Both day fields restricted: either one matches
+		{"0 0 13 * 5", "2017-10-02 00:00", "2017-10-06 00:00"},
+		{"0 0 13 * 5", "2017-10-06 00:00", "2017-10-13 00:00"},
+		{"0 0 1,15 * 1", "2017-10-09 00:00", "2017-10-15 00:00"},
+
+		// Sunday is 0 and 7
+		{"0 0 * * 0", "2017-10-02 00:00", "2017-10-08 00:00"},
+		{"0 0 * * 7", "2017-10-02 00:00", "2017-10-08 00:00"},
+		{"0 0 * * 5-7", "2017-10-02 00:00", "2017-10-06 00:00"},
+
+		// Leap days and days that never come
+		{"0 0 29 2 *", "2017-03-01 00:00", "2020-02-29 00:00"},
+		{"0 0 30 2 *", "2017-03-01 00:00", ""},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.expression)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.expression, err)
+			continue
+		}
+		after := date(test.after).Add(30 * time.Second)
+		var want time.Time
+		if test.want != "" {
+			want = date(test.want)
+		}
+		if got := s.Next(after); !got.Equal(want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", test.expression, test.after, got, want)
+		}
+	}
+}
+
+func TestPrev(t *testing.T) {
+	tests := []struct {
+		expression string
+		at         string
+		want       string
+	}{
+		// The firing at t itself counts
+		{"30 8 * * *", "2017-10-02 08:30", "2017-10-02 08:30"},
+		{"30 8 * * *", "2017-10-02 08:29", "2017-10-01 08:30"},
+
+		// Ranges, lists and steps
+		{"0 9-17 * * *", "2017-10-02 08:59", "2017-10-01 17:00"},
+		{"*/15 * * * *", "2017-10-02 10:14", "2017-10-02 10:00"},
+		{"0-30/10 * * * *", "2017-10-02 10:59", "2017-10-02 10:30"},
+		{"0 6,18 * * *", "2017-10-02 17:59", "2017-10-02 06:00"},
+		{"0 0 * 2-4/2 *", "2017-10-02 00:00", "2017-04-30 00:00"},
+
+		// Only one day field restricted: both have to match
+		{"0 0 * * 1-5", "2017-10-08 12:00", "2017-10-06 00:00"},
+		{"0 0 13 * *", "2017-10-02 00:00", "2017-09-13 00:00"},
+
+		// Both day fields restricted: either one matches
+		{"0 0 13 * 5", "2017-10-12 00:00", "2017-10-06 00:00"},
+		{"0 0 13 * 5", "2017-10-05 00:00", "2017-09-29 00:00"},
+
+		// Sunday is 0 and 7
+		{"0 0 * * 7", "2017-10-07 00:00", "2017-10-01 00:00"},
+
+		// Leap days and days that never come
+		{"0 0 29 2 *", "2017-10-02 00:00", "2016-02-29 00:00"},
+		{"0 0 30 2 *", "2017-10-02 00:00", ""},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.expression)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.expression, err)
+			continue
+		}
+		at := date(test.at).Add(30 * time.Second)
+		var want time.Time
+		if test.want != "" {
+			want = date(test.want)
+		}
+		if got := s.Prev(at); !got.Equal(want) {
+			t.Errorf("%q.Prev(%s) = %s, want %s", test.expression, test.at, got, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SourceComponent is the component recorded as the source of the operator's events.
+const SourceComponent = "weblogic-operator"
+
+// Record records an Event on the given object. Failing to record it is logged
+// but not returned, it must not fail the operation it reports on.
+func Record(clientset kubernetes.Interface, object v1.ObjectReference, eventType, reason, message string) {
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    object.Namespace,
+			GenerateName: object.Name + ".",
+		},
+		InvolvedObject: object,
+		Reason:         reason,
+		Message:        message,
+		Source:         v1.EventSource{Component: SourceComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventType,
+	}
+
+	_, err := clientset.CoreV1().Events(object.Namespace).Create(event)
+	if err != nil {
+		glog.Errorf("Could not record event %s for %s %s: %s", reason, object.Kind, object.Name, err)
+	}
+}
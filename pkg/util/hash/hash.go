@@ -0,0 +1,34 @@
+package hash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// ForString returns a short, stable digest of the given content suitable for
+// use in annotations and object names.
+func ForString(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// ForMap returns a short, stable digest of the given key/value pairs, such as
+// the data of a ConfigMap.
+func ForMap(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var content bytes.Buffer
+	for _, key := range keys {
+		content.WriteString(key)
+		content.WriteByte(0)
+		content.WriteString(data[key])
+		content.WriteByte(0)
+	}
+	return ForString(content.String())
+}
@@ -0,0 +1,132 @@
+package restart
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/constants"
+)
+
+// ReadyBackoff bounds how long a rolling restart waits for a replacement pod
+// to become ready before giving up.
+var ReadyBackoff = wait.Backoff{
+	Steps:    120,
+	Duration: 5 * time.Second,
+	Factor:   1.0,
+	Jitter:   0.1,
+}
+
+var (
+	inProgressLock sync.Mutex
+	inProgress     = map[string]bool{}
+)
+
+// IsPodReady returns true if the pod is running and reports the Ready condition.
+func IsPodReady(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsPodScalingDown returns true if the server of the pod was shut down for a
+// scale down, so the ReplicaSet is about to remove the pod.
+func IsPodScalingDown(pod *v1.Pod) bool {
+	return pod.Annotations[constants.ScaleDownAnnotation] == "true"
+}
+
+// RollingRestartAsync runs RollingRestart in the background unless a restart
+// of the same pods is already running.
+func RollingRestartAsync(clientset kubernetes.Interface, namespace, selector string) {
+	key := namespace + "/" + selector
+
+	inProgressLock.Lock()
+	if inProgress[key] {
+		inProgressLock.Unlock()
+		glog.V(2).Infof("Rolling restart of pods %s is already in progress", key)
+		return
+	}
+	inProgress[key] = true
+	inProgressLock.Unlock()
+
+	go func() {
+		defer func() {
+			inProgressLock.Lock()
+			delete(inProgress, key)
+			inProgressLock.Unlock()
+		}()
+
+		if err := RollingRestart(clientset, namespace, selector); err != nil {
+			glog.Errorf("Rolling restart of pods %s failed: %s", key, err)
+		}
+	}()
+}
+
+// RollingRestart deletes the pods matching the selector one at a time. After
+// each deletion it waits for the owning controller's replacement to become
+// ready before moving on to the next pod.
+func RollingRestart(clientset kubernetes.Interface, namespace, selector string) error {
+	opts := metav1.ListOptions{LabelSelector: selector}
+	pods, err := clientset.CoreV1().Pods(namespace).List(opts)
+	if err != nil {
+		glog.Errorf("Unable to list pods %s: %s", selector, err)
+		return err
+	}
+
+	restarted := map[string]bool{}
+	readyBefore := 0
+	for i := range pods.Items {
+		if IsPodReady(&pods.Items[i]) {
+			readyBefore++
+		}
+	}
+
+	for _, pod := range pods.Items {
+		// Pods scaling down are removed rather than replaced
+		if pod.DeletionTimestamp != nil || IsPodScalingDown(&pod) {
+			continue
+		}
+
+		glog.V(2).Infof("Restarting pod %s/%s", namespace, pod.Name)
+		err = clientset.CoreV1().Pods(namespace).Delete(pod.Name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		restarted[pod.Name] = true
+
+		err = wait.ExponentialBackoff(ReadyBackoff, func() (bool, error) {
+			current, err := clientset.CoreV1().Pods(namespace).List(opts)
+			if err != nil {
+				glog.V(4).Infof("Unable to list pods %s: %s", selector, err)
+				return false, nil
+			}
+
+			ready := 0
+			for i := range current.Items {
+				if !restarted[current.Items[i].Name] && IsPodReady(&current.Items[i]) {
+					ready++
+				}
+			}
+			return ready >= readyBefore, nil
+		})
+		if err == wait.ErrWaitTimeout {
+			return fmt.Errorf("timed out waiting for a replacement of pod %s to become ready", pod.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package version
+
+// Image is the name of the operator image the binaries are shipped in, set
+// when building with -ldflags "-X weblogic-operator/pkg/version.Image=<name>".
+var Image = "gcr.io/fmwplt-gcp/weblogic-operator"
+
+// Version is the tag of the operator image the binaries are shipped in, set
+// when building with -ldflags "-X weblogic-operator/pkg/version.Version=<tag>".
+var Version = "latest"
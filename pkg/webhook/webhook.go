@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"weblogic-operator/pkg/server"
+)
+
+// ScalingPath is the path prefix of the scaling endpoint. WLDF REST actions
+// POST to <ScalingPath><namespace>/<domain>/<cluster>/scaleUp or scaleDown,
+// optionally with a count query parameter (defaults to 1).
+const ScalingPath = "/wldf/scaling/"
+
+const (
+	scaleUpAction   = "scaleUp"
+	scaleDownAction = "scaleDown"
+)
+
+// Config configures the scaling webhook.
+type Config struct {
+	// Address the webhook listens on, e.g. ":9999".
+	Address string
+	// CredentialsDir holds the username and password files of a
+	// kubernetes.io/basic-auth Secret. The webhook is disabled without it.
+	CredentialsDir string
+	// CertFile and KeyFile serve the webhook over HTTPS. Both are required
+	// unless AllowHTTP is set.
+	CertFile string
+	KeyFile  string
+	// AllowHTTP serves the webhook over plain HTTP when no certificate is
+	// given, sending the credentials in the clear.
+	AllowHTTP bool
+}
+
+// tlsEnabled returns true if the webhook is served over HTTPS.
+func (c Config) tlsEnabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// Enabled returns true if the webhook should be served.
+func (c Config) Enabled() bool {
+	return c.Address != "" && c.CredentialsDir != ""
+}
+
+// ScalingWebhook accepts scaling notifications from WLDF policies and adjusts
+// serversToRun of the WebLogicManagedServer running the named cluster.
+type ScalingWebhook struct {
+	config     Config
+	client     kubernetes.Interface
+	restClient *rest.RESTClient
+	managed    func(namespace string) bool
+	username   []byte
+	password   []byte
+}
+
+// NewScalingWebhook creates a new ScalingWebhook, reading its credentials.
+// Only the namespaces for which managed returns true can be scaled.
+func NewScalingWebhook(config Config, kubeClient kubernetes.Interface, restClient *rest.RESTClient, managed func(namespace string) bool) (*ScalingWebhook, error) {
+	if !config.tlsEnabled() && !config.AllowHTTP {
+		return nil, fmt.Errorf("the scaling webhook needs a certificate and private key to serve HTTPS, or plain HTTP to be allowed")
+	}
+	username, err := readCredential(config.CredentialsDir, "username")
+	if err != nil {
+		return nil, err
+	}
+	password, err := readCredential(config.CredentialsDir, "password")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScalingWebhook{
+		config:     config,
+		client:     kubeClient,
+		restClient: restClient,
+		managed:    managed,
+		username:   username,
+		password:   password,
+	}, nil
+}
+
+// readCredential reads a file of a basic-auth Secret, which must not be empty.
+func readCredential(dir, name string) ([]byte, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("reading scaling webhook %s: %v", name, err)
+	}
+	credential := strings.TrimSpace(string(content))
+	if credential == "" {
+		return nil, fmt.Errorf("scaling webhook %s in %s is empty", name, dir)
+	}
+	return []byte(credential), nil
+}
+
+// Run serves the webhook until stopChan is closed.
+func (w *ScalingWebhook) Run(stopChan <-chan struct{}) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ScalingPath, w.handleScaling)
+	httpServer := &http.Server{Addr: w.config.Address, Handler: mux}
+
+	go func() {
+		<-stopChan
+		httpServer.Close()
+	}()
+
+	var err error
+	if w.config.tlsEnabled() {
+		glog.Infof("Serving the WLDF scaling webhook over HTTPS on %s", w.config.Address)
+		err = httpServer.ListenAndServeTLS(w.config.CertFile, w.config.KeyFile)
+	} else {
+		glog.Warningf("Serving the WLDF scaling webhook over plain HTTP on %s", w.config.Address)
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		glog.Errorf("Scaling webhook failed: %s", err)
+	}
+}
+
+func (w *ScalingWebhook) authenticated(r *http.Request) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	usernameMatches := subtle.ConstantTimeCompare([]byte(username), w.username) == 1
+	passwordMatches := subtle.ConstantTimeCompare([]byte(password), w.password) == 1
+	return usernameMatches && passwordMatches
+}
+
+func (w *ScalingWebhook) handleScaling(rw http.ResponseWriter, r *http.Request) {
+	if !w.authenticated(r) {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="weblogic-operator"`)
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(rw, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, ScalingPath), "/")
+	if len(parts) != 4 {
+		http.Error(rw, "expected "+ScalingPath+"<namespace>/<domain>/<cluster>/<action>", http.StatusNotFound)
+		return
+	}
+	namespace, domainName, clusterName, action := parts[0], parts[1], parts[2], parts[3]
+	if !w.managed(namespace) {
+		http.Error(rw, fmt.Sprintf("namespace %s is not managed by the operator", namespace), http.StatusForbidden)
+		return
+	}
+
+	count := int32(1)
+	if value := r.URL.Query().Get("count"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || parsed < 1 {
+			http.Error(rw, "count must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		count = int32(parsed)
+	}
+
+	var delta int32
+	switch action {
+	case scaleUpAction:
+		delta = count
+	case scaleDownAction:
+		delta = -count
+	default:
+		http.Error(rw, fmt.Sprintf("unknown action %q, expected %s or %s", action, scaleUpAction, scaleDownAction), http.StatusNotFound)
+		return
+	}
+
+	glog.V(2).Infof("WLDF requested %s of cluster %s in domain %s/%s by %d", action, clusterName, namespace, domainName, count)
+	scaled, err := server.ScaleWebLogicManagedServer(w.client, w.restClient, namespace, domainName, clusterName, delta)
+	switch {
+	case err == nil:
+		fmt.Fprintf(rw, "%s scaled to %d servers\n", scaled.Name, scaled.Spec.ServersToRun)
+	case server.IsScalingRejected(err):
+		http.Error(rw, err.Error(), http.StatusConflict)
+	case errors.IsNotFound(err):
+		http.Error(rw, fmt.Sprintf("cluster %s of domain %s/%s not found", clusterName, namespace, domainName), http.StatusNotFound)
+	default:
+		glog.Errorf("Could not scale cluster %s of domain %s/%s: %s", clusterName, namespace, domainName, err)
+		http.Error(rw, "scaling failed", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,301 @@
+// Package wlsrest talks to the REST management API of a domain's admin server.
+package wlsrest
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"weblogic-operator/pkg/constants"
+	"weblogic-operator/pkg/types"
+)
+
+const (
+	managementPath = "/management/weblogic/latest"
+	requestTimeout = 30 * time.Second
+	// Starting a server waits for it to reach RUNNING
+	startTimeout = 10 * time.Minute
+)
+
+// Client calls the REST management API of a domain's admin server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	username   string
+	password   string
+}
+
+// NewClientForDomain returns a Client for the admin server of the given
+// domain, reached through the domain Service, authenticated with the
+// credentials of the domain's admin Secret. HTTPS is used when TLS is
+// enabled, trusting the CA of the domain's TLS Secret.
+func NewClientForDomain(clientset kubernetes.Interface, domain *types.WebLogicDomain) (*Client, error) {
+	credentials, err := clientset.CoreV1().Secrets(domain.Namespace).Get(domain.AdminSecretName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	host := fmt.Sprintf("%s.%s.svc", domain.Name, domain.Namespace)
+	client := &Client{
+		baseURL:    fmt.Sprintf("http://%s:%d%s", host, domain.AdminPort(), managementPath),
+		httpClient: &http.Client{},
+		username:   string(credentials.Data[constants.AdminUsernameKey]),
+		password:   string(credentials.Data[constants.AdminPasswordKey]),
+	}
+	if domain.Spec.TLS == nil {
+		return client, nil
+	}
+
+	secret, err := clientset.CoreV1().Secrets(domain.Namespace).Get(domain.TLSSecretName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(secret.Data[constants.TLSCACertificateKey]) {
+		return nil, fmt.Errorf("no CA certificate found in secret %s", secret.Name)
+	}
+	client.baseURL = fmt.Sprintf("https://%s:%d%s", host, domain.Spec.Ports.AdminSSLPort, managementPath)
+	client.httpClient = &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+	return client, nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into result, if given.
+func (c *Client) do(method, path string, body interface{}, timeout time.Duration, result interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	// Required by WebLogic for requests modifying the domain
+	req.Header.Set("X-Requested-By", "weblogic-operator")
+
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s returned %s: %s", method, path, resp.Status, content)
+	}
+	if result == nil || len(content) == 0 {
+		return nil
+	}
+	return json.Unmarshal(content, result)
+}
+
+type componentRuntimes struct {
+	Items []struct {
+		OpenSessionsCurrentCount int `json:"openSessionsCurrentCount"`
+	} `json:"items"`
+}
+
+type sessionsSearchResult struct {
+	ServerRuntimes struct {
+		Items []struct {
+			Name                string `json:"name"`
+			ApplicationRuntimes struct {
+				Items []struct {
+					ComponentRuntimes componentRuntimes `json:"componentRuntimes"`
+				} `json:"items"`
+			} `json:"applicationRuntimes"`
+		} `json:"items"`
+	} `json:"serverRuntimes"`
+}
+
+// OpenSessions returns the number of open HTTP sessions of each running server.
+func (c *Client) OpenSessions() (map[string]int, error) {
+	query := map[string]interface{}{
+		"links":  []string{},
+		"fields": []string{},
+		"children": map[string]interface{}{
+			"serverRuntimes": map[string]interface{}{
+				"links":  []string{},
+				"fields": []string{"name"},
+				"children": map[string]interface{}{
+					"applicationRuntimes": map[string]interface{}{
+						"links":  []string{},
+						"fields": []string{},
+						"children": map[string]interface{}{
+							"componentRuntimes": map[string]interface{}{
+								"links":  []string{},
+								"fields": []string{"openSessionsCurrentCount"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result := &sessionsSearchResult{}
+	err := c.do(http.MethodPost, "/domainRuntime/search", query, requestTimeout, result)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := map[string]int{}
+	for _, server := range result.ServerRuntimes.Items {
+		count := 0
+		for _, application := range server.ApplicationRuntimes.Items {
+			for _, component := range application.ComponentRuntimes.Items {
+				count += component.OpenSessionsCurrentCount
+			}
+		}
+		sessions[server.Name] = count
+	}
+	return sessions, nil
+}
+
+// ShutdownServer shuts the named server down as configured, returning once it
+// is down or the shutdown timed out.
+func (c *Client) ShutdownServer(serverName string, shutdown *types.ShutdownOptions) error {
+	path := fmt.Sprintf("/domainRuntime/serverLifeCycleRuntimes/%s/", serverName)
+	timeout := time.Duration(shutdown.Timeout())*time.Second + requestTimeout
+
+	if shutdown.Forced() {
+		return c.do(http.MethodPost, path+"forceShutdown", map[string]interface{}{}, timeout, nil)
+	}
+	return c.do(http.MethodPost, path+"shutdown", map[string]interface{}{
+		"timeout":            shutdown.Timeout(),
+		"ignoreSessions":     shutdown.SessionsIgnored(),
+		"waitForAllSessions": false,
+	}, timeout, nil)
+}
+
+// SuspendServer stops the named server from accepting new work, letting
+// in-flight work and, unless ignored, HTTP sessions complete first.
+func (c *Client) SuspendServer(serverName string, shutdown *types.ShutdownOptions) error {
+	path := fmt.Sprintf("/domainRuntime/serverLifeCycleRuntimes/%s/", serverName)
+	timeout := time.Duration(shutdown.Timeout())*time.Second + requestTimeout
+
+	if shutdown.Forced() {
+		return c.do(http.MethodPost, path+"forceSuspend", map[string]interface{}{}, timeout, nil)
+	}
+	return c.do(http.MethodPost, path+"suspend", map[string]interface{}{
+		"timeout":        shutdown.Timeout(),
+		"ignoreSessions": shutdown.SessionsIgnored(),
+	}, timeout, nil)
+}
+
+// StartServer starts the named server through the Node Manager of its machine,
+// returning once the server is running.
+func (c *Client) StartServer(serverName string) error {
+	path := fmt.Sprintf("/domainRuntime/serverLifeCycleRuntimes/%s/start", serverName)
+	return c.do(http.MethodPost, path, map[string]interface{}{}, startTimeout, nil)
+}
+
+// RestartServer forces the named server down and starts it again through the
+// Node Manager of its machine, keeping the pod the server runs in.
+func (c *Client) RestartServer(serverName string) error {
+	err := c.ShutdownServer(serverName, &types.ShutdownOptions{Type: types.ShutdownForced})
+	if err != nil {
+		return err
+	}
+	return c.StartServer(serverName)
+}
+
+// ServerState is the life cycle state and health of a server.
+type ServerState struct {
+	Name   string
+	State  string
+	Health string
+}
+
+type lifeCycleRuntimes struct {
+	Items []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	} `json:"items"`
+}
+
+type serverRuntimes struct {
+	Items []struct {
+		Name        string `json:"name"`
+		HealthState struct {
+			State string `json:"state"`
+		} `json:"healthState"`
+	} `json:"items"`
+}
+
+// ServerStates returns the state of every server of the domain. The health
+// of servers that are not running is left empty.
+func (c *Client) ServerStates() (map[string]ServerState, error) {
+	lifeCycles := &lifeCycleRuntimes{}
+	err := c.do(http.MethodGet, "/domainRuntime/serverLifeCycleRuntimes?links=none&fields=name,state", nil, requestTimeout, lifeCycles)
+	if err != nil {
+		return nil, err
+	}
+	runtimes := &serverRuntimes{}
+	err = c.do(http.MethodGet, "/domainRuntime/serverRuntimes?links=none&fields=name,healthState", nil, requestTimeout, runtimes)
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]ServerState{}
+	for _, item := range lifeCycles.Items {
+		states[item.Name] = ServerState{Name: item.Name, State: item.State}
+	}
+	for _, item := range runtimes.Items {
+		state := states[item.Name]
+		state.Name = item.Name
+		state.Health = item.HealthState.State
+		states[item.Name] = state
+	}
+	return states, nil
+}
+
+type listenAddresses struct {
+	Items []struct {
+		Name          string `json:"name"`
+		ListenAddress string `json:"listenAddress"`
+	} `json:"items"`
+}
+
+// ServerAddresses returns the IP address each running server listens on.
+func (c *Client) ServerAddresses() (map[string]string, error) {
+	runtimes := &listenAddresses{}
+	err := c.do(http.MethodGet, "/domainRuntime/serverRuntimes?links=none&fields=name,listenAddress", nil, requestTimeout, runtimes)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := map[string]string{}
+	for _, item := range runtimes.Items {
+		// Reported as host/address
+		address := item.ListenAddress
+		if i := strings.LastIndex(address, "/"); i >= 0 {
+			address = address[i+1:]
+		}
+		if address != "" {
+			addresses[item.Name] = address
+		}
+	}
+	return addresses, nil
+}
@@ -0,0 +1,253 @@
+// Package wlst renders the WLST scripts the operator hands to WebLogic pods.
+package wlst
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"weblogic-operator/pkg/types"
+)
+
+// domainModelPrelude holds the helpers used by the generated statements. Every
+// resource of the model is deleted and created again so the script can be
+// re-applied each time the admin server starts. The resources created are
+// recorded in the domain home, so those of an earlier model missing from the
+// current one are deleted.
+const domainModelPrelude = `# Generated by weblogic-operator. Do not edit.
+import os
+import sys
+
+domainHome = sys.argv[1]
+appliedFile = domainHome + '/config/operator-domain-model.txt'
+created = []
+
+def readApplied():
+    applied = []
+    if os.path.exists(appliedFile):
+        f = open(appliedFile)
+        for line in f.readlines():
+            entry = tuple(line.rstrip('\n').split('\t'))
+            if len(entry) == 3:
+                applied.append(entry)
+        f.close()
+    return applied
+
+applied = readApplied()
+
+def exists(path):
+    try:
+        cd(path)
+        return True
+    except:
+        return False
+
+def recreate(name, mbeanType, parent):
+    created.append((parent, mbeanType, name))
+    cd(parent)
+    if exists(parent.rstrip('/') + '/' + mbeanType + '/' + name):
+        cd(parent)
+        delete(name, mbeanType)
+    cd(parent)
+    return create(name, mbeanType)
+
+def deleteRemoved():
+    # Delete in the reverse order of creation so users go before what they use
+    applied.reverse()
+    for (parent, mbeanType, name) in applied:
+        if (parent, mbeanType, name) not in created and exists(parent.rstrip('/') + '/' + mbeanType + '/' + name):
+            cd(parent)
+            delete(name, mbeanType)
+    cd('/')
+
+def writeApplied():
+    f = open(appliedFile, 'w')
+    for entry in created:
+        f.write('\t'.join(entry) + '\n')
+    f.close()
+
+def createDataSource(name, jndiName, url, driverName, user, passwordEnv, initialCapacity, maxCapacity, testTableName, targets):
+    recreate(name, 'JDBCSystemResource', '/')
+    base = '/JDBCSystemResource/' + name + '/JdbcResource/' + name
+    cd(base)
+    cmo.setName(name)
+    create('dataSourceParams', 'JDBCDataSourceParams')
+    cd(base + '/JDBCDataSourceParams/NO_NAME_0')
+    set('JNDIName', java.lang.String(jndiName))
+    set('GlobalTransactionsProtocol', java.lang.String('None'))
+    cd(base)
+    create('driverParams', 'JDBCDriverParams')
+    cd(base + '/JDBCDriverParams/NO_NAME_0')
+    set('DriverName', driverName)
+    set('URL', url)
+    if passwordEnv:
+        set('PasswordEncrypted', os.environ.get(passwordEnv, ''))
+    create('properties', 'Properties')
+    cd('Properties/NO_NAME_0')
+    if user:
+        create('user', 'Property')
+        cd('Property/user')
+        cmo.setValue(user)
+    cd(base)
+    create('poolParams', 'JDBCConnectionPoolParams')
+    cd(base + '/JDBCConnectionPoolParams/NO_NAME_0')
+    if initialCapacity > 0:
+        set('InitialCapacity', initialCapacity)
+    if maxCapacity > 0:
+        set('MaxCapacity', maxCapacity)
+    if testTableName:
+        set('TestTableName', testTableName)
+    cd('/')
+    assign('JDBCSystemResource', name, 'Target', targets)
+
+def createJMSServer(name, target):
+    recreate(name, 'JMSServer', '/')
+    cd('/')
+    assign('JMSServer', name, 'Target', target)
+
+def createJMSModule(name, targets, jmsServers, connectionFactories, queues, topics):
+    recreate(name, 'JMSSystemResource', '/')
+    cd('/')
+    assign('JMSSystemResource', name, 'Target', targets)
+    subDeployment = name + '-sub'
+    if jmsServers:
+        cd('/JMSSystemResource/' + name)
+        create(subDeployment, 'SubDeployment')
+        cd('/')
+        assign('JMSSystemResource.SubDeployment', name + '.' + subDeployment, 'Target', jmsServers)
+    base = '/JMSSystemResource/' + name + '/JmsResource/NO_NAME_0'
+    for (cfName, jndiName) in connectionFactories:
+        cd(base)
+        create(cfName, 'ConnectionFactory')
+        cd(base + '/ConnectionFactory/' + cfName)
+        set('JNDIName', jndiName)
+        set('DefaultTargetingEnabled', true)
+    for (mbeanType, destinations) in [('Queue', queues), ('Topic', topics)]:
+        for (destName, jndiName) in destinations:
+            cd(base)
+            create(destName, mbeanType)
+            cd(base + '/' + mbeanType + '/' + destName)
+            set('JNDIName', jndiName)
+            if jmsServers:
+                set('SubDeploymentName', subDeployment)
+
+def createWorkManager(domainName, name, targets, minThreads, maxThreads):
+    base = '/SelfTuning/' + domainName
+    if minThreads > 0:
+        recreate(name + '-min-threads', 'MinThreadsConstraint', base)
+        cd(base + '/MinThreadsConstraint/' + name + '-min-threads')
+        set('Count', minThreads)
+        set('Target', targets)
+    if maxThreads > 0:
+        recreate(name + '-max-threads', 'MaxThreadsConstraint', base)
+        cd(base + '/MaxThreadsConstraint/' + name + '-max-threads')
+        set('Count', maxThreads)
+        set('Target', targets)
+    recreate(name, 'WorkManager', base)
+    cd(base + '/WorkManager/' + name)
+    set('Target', targets)
+    if minThreads > 0:
+        set('MinThreadsConstraint', name + '-min-threads')
+    if maxThreads > 0:
+        set('MaxThreadsConstraint', name + '-max-threads')
+
+def createAppDeployment(name, sourcePath, moduleType, stagingMode, targets):
+    recreate(name, 'AppDeployment', '/')
+    cd('/AppDeployment/' + name)
+    set('SourcePath', sourcePath)
+    if moduleType:
+        set('ModuleType', moduleType)
+    set('StagingMode', stagingMode)
+    cd('/')
+    assign('AppDeployment', name, 'Target', targets)
+
+`
+
+// NewDomainModelScript renders an offline WLST script applying the given model
+// to the domain. The script expects the domain home as its only argument.
+func NewDomainModelScript(domain *types.WebLogicDomain, model *types.WebLogicDomainModel) string {
+	var b bytes.Buffer
+	b.WriteString(domainModelPrelude)
+	b.WriteString("readDomain(domainHome)\n\n")
+
+	defaultTargets := domain.ManagedServerNames()
+
+	for _, ds := range model.DataSources {
+		passwordEnv := ""
+		if ds.PasswordSecret != nil {
+			passwordEnv = DataSourcePasswordEnvVarName(ds)
+		}
+		fmt.Fprintf(&b, "createDataSource(%s, %s, %s, %s, %s, %s, %d, %d, %s, %s)\n",
+			quote(ds.Name), quote(ds.JNDIName), quote(ds.URL), quote(ds.DriverName), quote(ds.User),
+			quote(passwordEnv), ds.InitialCapacity, ds.MaxCapacity, quote(ds.TestTableName),
+			targets(ds.Targets, defaultTargets))
+	}
+
+	for _, server := range model.JMSServers {
+		fmt.Fprintf(&b, "createJMSServer(%s, %s)\n", quote(server.Name), quote(server.Target))
+	}
+
+	for _, module := range model.JMSModules {
+		factories := make([]string, 0, len(module.ConnectionFactories))
+		for _, cf := range module.ConnectionFactories {
+			factories = append(factories, fmt.Sprintf("(%s, %s)", quote(cf.Name), quote(cf.JNDIName)))
+		}
+		fmt.Fprintf(&b, "createJMSModule(%s, %s, %s, [%s], %s, %s)\n",
+			quote(module.Name), targets(module.Targets, defaultTargets), quote(strings.Join(module.JMSServers, ",")),
+			strings.Join(factories, ", "), destinations(module.Queues), destinations(module.Topics))
+	}
+
+	for _, wm := range model.WorkManagers {
+		fmt.Fprintf(&b, "createWorkManager(%s, %s, %s, %d, %d)\n",
+			quote(domain.Name), quote(wm.Name), targets(wm.Targets, defaultTargets), wm.MinThreads, wm.MaxThreads)
+	}
+
+	for _, app := range model.AppDeployments {
+		stagingMode := app.StagingMode
+		if stagingMode == "" {
+			stagingMode = "nostage"
+		}
+		fmt.Fprintf(&b, "createAppDeployment(%s, %s, %s, %s, %s)\n",
+			quote(app.Name), quote(app.SourcePath), quote(app.ModuleType), quote(stagingMode),
+			targets(app.Targets, defaultTargets))
+	}
+
+	b.WriteString("\ndeleteRemoved()\nupdateDomain()\ncloseDomain()\nwriteApplied()\nexit()\n")
+	return b.String()
+}
+
+// DataSourcePasswordEnvVarName returns the environment variable the admin
+// server container exposes a data source password under.
+func DataSourcePasswordEnvVarName(ds types.DataSource) string {
+	name := strings.ToUpper(ds.Name)
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return "DS_" + name + "_PASSWORD"
+}
+
+func destinations(dests []types.JMSDestination) string {
+	items := make([]string, 0, len(dests))
+	for _, d := range dests {
+		items = append(items, fmt.Sprintf("(%s, %s)", quote(d.Name), quote(d.JNDIName)))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func targets(names []string, defaults []string) string {
+	if len(names) == 0 {
+		names = defaults
+	}
+	return quote(strings.Join(names, ","))
+}
+
+// quote renders s as a single quoted Jython string literal.
+func quote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `'`, `\'`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return "'" + s + "'"
+}
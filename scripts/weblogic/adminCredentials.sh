@@ -0,0 +1,15 @@
+#!/bin/bash
+# Sourced by the scripts calling the admin server or the Node Manager. Reads
+# the credentials of the domain administrator from the Secret mounted into the
+# pod. Pass curl -K <(adminCurlConfig) to keep them off the command line.
+
+ADMIN_CREDENTIALS_DIR=/u01/oracle/admin-credentials
+ADMIN_USERNAME=$(cat ${ADMIN_CREDENTIALS_DIR}/username)
+ADMIN_PASSWORD=$(cat ${ADMIN_CREDENTIALS_DIR}/password)
+
+adminCurlConfig() {
+    local user="${ADMIN_USERNAME}:${ADMIN_PASSWORD}"
+    user=${user//\\/\\\\}
+    user=${user//\"/\\\"}
+    printf 'user = "%s"\n' "${user}"
+}
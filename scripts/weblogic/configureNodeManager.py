@@ -0,0 +1,56 @@
+import sys
+
+# The credentials of the domain administrator are read from the Secret
+# mounted into the pod, to keep them out of the process list
+def readAdminCredential(key):
+    f = open('/u01/oracle/admin-credentials/' + key)
+    value = f.read().strip()
+    f.close()
+    return value
+
+# Points the machine of every managed server at the Node Manager running in
+# the server's pod, reached through the Service of the server, and sets the
+# credentials the admin server and the start script connect with.
+
+try:
+    domainHome = sys.argv[1]
+    domainName = sys.argv[2]
+    nodeManagerPort = int(sys.argv[3])
+    username = readAdminCredential('username')
+    password = readAdminCredential('password')
+
+    print('DOMAIN_HOME              : [%s]' % domainHome);
+    print('DOMAIN_NAME              : [%s]' % domainName);
+    print('NODE_MANAGER_PORT        : [%s]' % nodeManagerPort);
+
+    readDomain(domainHome)
+
+    cd('/SecurityConfiguration/' + domainName)
+    set('NodeManagerUsername', username)
+    set('NodeManagerPasswordEncrypted', password)
+
+    cd('/')
+    for server in cmo.getServers():
+        serverName = server.getName()
+        if serverName == 'AdminServer':
+            continue
+
+        machineName = 'Machine-' + serverName
+        cd('/Machines/%s/NodeManager/%s' % (machineName, machineName))
+        set('NMType', 'Plain')
+        set('ListenAddress', '%s-%s' % (domainName, serverName))
+        set('ListenPort', nodeManagerPort)
+        cd('/')
+
+    updateDomain()
+    closeDomain()
+    print "Node Manager Configured Successfully "
+
+    # Exit WLST
+    # =========
+    exit()
+
+except Exception, e:
+    e.printStackTrace()
+    dumpStack()
+    raise ("Node Manager Configuration Failed")
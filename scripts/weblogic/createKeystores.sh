@@ -0,0 +1,20 @@
+#!/bin/bash
+# Builds the identity and trust keystores of the server of this pod from the
+# certificates mounted at /u01/oracle/tls. The keystores stay in the pod; the
+# domain configuration finds them at the same path in every pod. Passwords are
+# passed in the environment so they do not show in the process list.
+
+KEYSTORE_DIR=/u01/oracle/keystores
+export KEYSTORE_PASSWORD=${KEYSTORE_PASSWORD:-changeit}
+
+mkdir -p ${KEYSTORE_DIR}
+rm -f ${KEYSTORE_DIR}/*.jks ${KEYSTORE_DIR}/*.p12
+
+keytool -importcert -noprompt -alias ca -file /u01/oracle/tls/ca.crt \
+        -keystore ${KEYSTORE_DIR}/trust.jks -storepass:env KEYSTORE_PASSWORD
+
+openssl pkcs12 -export -in /u01/oracle/tls/tls.crt -inkey /u01/oracle/tls/tls.key -certfile /u01/oracle/tls/ca.crt -name identity \
+        -out ${KEYSTORE_DIR}/identity.p12 -passout env:KEYSTORE_PASSWORD
+keytool -importkeystore -noprompt -srckeystore ${KEYSTORE_DIR}/identity.p12 -srcstoretype PKCS12 -srcstorepass:env KEYSTORE_PASSWORD \
+        -destkeystore ${KEYSTORE_DIR}/identity.jks -deststoretype JKS -deststorepass:env KEYSTORE_PASSWORD
+rm -f ${KEYSTORE_DIR}/identity.p12
@@ -3,26 +3,86 @@ echo ---------------------------------------------------------------------------
 echo Kubernetes Domain Setup
 echo ------------------------------------------------------------------------------------------
 
+KEYSTORE_DIR=/u01/oracle/keystores
+. /u01/oracle/scripts/adminCredentials.sh
+
+echo Start - TLS Keystores
+if [ "${TLS_ENABLED}" = "true" ]; then
+    /u01/oracle/scripts/createKeystores.sh
+fi
+echo End - TLS Keystores
+
+echo ------------------------------------------------------------------------------------------
+
 echo Start - Domain Setup
 if [ ! -d ${DOMAIN_HOME} ]; then
-    $ORACLE_HOME/oracle_common/common/bin/wlst.sh -skipWLSModuleScanning /u01/oracle/user_projects/kubeCreateDomain.py \
-                                                        $MY_POD_NAME $ORACLE_HOME $DOMAIN_NAME $DOMAIN_HOME $MANAGED_SERVER_COUNT "7001" "weblogic" "welcome1" \
+    $ORACLE_HOME/oracle_common/common/bin/wlst.sh -skipWLSModuleScanning /u01/oracle/scripts/kubeCreateDomain.py \
+                                                        $MY_POD_NAME $ORACLE_HOME $DOMAIN_NAME $DOMAIN_HOME $MANAGED_SERVER_COUNT "${ADMIN_PORT}" \
+                                                        "${MANAGED_SERVER_BASE_PORT}" "${MANAGED_SERVER_PORT_STEP}" "${ADMIN_SSL_PORT}" "${MANAGED_SERVER_SSL_BASE_PORT}" "${T3_CHANNELS}" \
+                                                        "${TLS_ENABLED:-false}" "${KEYSTORE_DIR}" \
+                                                        "${CLUSTER_PORT:-0}" "${CLUSTER_SSL_PORT:-0}" \
                                                         >> /u01/oracle/user_projects/domainSetup"_${DOMAIN_NAME}".log 2>&1
 fi
 echo End - Domain Setup
 
 echo ------------------------------------------------------------------------------------------
 
+echo Start - Domain Model
+if [ -d ${DOMAIN_HOME} ] && [ -f /u01/oracle/domain-model/applyDomainModel.py ]; then
+    $ORACLE_HOME/oracle_common/common/bin/wlst.sh -skipWLSModuleScanning /u01/oracle/domain-model/applyDomainModel.py \
+                                                        $DOMAIN_HOME \
+                                                        >> /u01/oracle/user_projects/domainModel"_${DOMAIN_NAME}".log 2>&1
+fi
+echo End - Domain Model
+
+echo ------------------------------------------------------------------------------------------
+
+echo Start - Config Overrides
+if [ -d ${DOMAIN_HOME} ] && [ -d /u01/oracle/config-overrides ]; then
+    mkdir -p ${DOMAIN_HOME}/optconfig
+    rm -f ${DOMAIN_HOME}/optconfig/*.xml
+    for override in /u01/oracle/config-overrides/*.xml; do
+        [ -f "$override" ] && cp "$override" ${DOMAIN_HOME}/optconfig/
+    done
+fi
+echo End - Config Overrides
+
+echo ------------------------------------------------------------------------------------------
+
+echo Start - Node Manager
+if [ -d ${DOMAIN_HOME} ] && [ "${NODE_MANAGER_ENABLED}" = "true" ]; then
+    # Point the machine of every managed server at the Node Manager in its pod
+    $ORACLE_HOME/oracle_common/common/bin/wlst.sh -skipWLSModuleScanning /u01/oracle/scripts/configureNodeManager.py \
+                                                        $DOMAIN_HOME $DOMAIN_NAME "${NODE_MANAGER_PORT}" \
+                                                        >> /u01/oracle/user_projects/nodeManager"_${DOMAIN_NAME}".log 2>&1
+fi
+echo End - Node Manager
+
+echo ------------------------------------------------------------------------------------------
+
 echo Start - Admin Start
 if [ -d ${DOMAIN_HOME} ]; then
     mkdir -p ${DOMAIN_HOME}/servers/AdminServer/security/
-    echo "username=weblogic" > ${DOMAIN_HOME}/servers/AdminServer/security/boot.properties
-    echo "password=welcome1" >> ${DOMAIN_HOME}/servers/AdminServer/security/boot.properties
+    echo "username=${ADMIN_USERNAME}" > ${DOMAIN_HOME}/servers/AdminServer/security/boot.properties
+    echo "password=${ADMIN_PASSWORD}" >> ${DOMAIN_HOME}/servers/AdminServer/security/boot.properties
     ${DOMAIN_HOME}/bin/setDomainEnv.sh
 
+    # Tells the readiness and liveness probes which server to check
+    echo "SERVER_NAME=AdminServer" > /tmp/weblogic-server.env
+    echo "SERVER_PORT=${ADMIN_PORT}" >> /tmp/weblogic-server.env
+
+    # USER_MEM_ARGS and JAVA_OPTIONS are set on the container by the operator
+    echo "USER_MEM_ARGS=${USER_MEM_ARGS}"
+    echo "JAVA_OPTIONS=${JAVA_OPTIONS}"
+    export USER_MEM_ARGS JAVA_OPTIONS
+
+    /u01/oracle/scripts/exportLogs.sh AdminServer
+
     ${DOMAIN_HOME}/bin/startWebLogic.sh
-    touch ${DOMAIN_HOME}/servers/AdminServer/logs/AdminServer.log
-    tail -f ${DOMAIN_HOME}/servers/AdminServer/logs/AdminServer.log &
+    # The log exporter sidecar prints the logs when they are exported
+    if [ -z "${LOG_EXPORT_DIR}" ]; then
+        tail -f ${DOMAIN_HOME}/servers/AdminServer/logs/AdminServer.log &
+    fi
 fi
 echo Stop - Admin Start
 
@@ -0,0 +1,23 @@
+#!/bin/bash
+# Prepares the log directory of the server named by the first argument. When
+# LOG_EXPORT_DIR is set the directory on the domain storage is replaced by a
+# link into the emptyDir read by the log exporter sidecar, so the server,
+# access and domain logs reach it. Otherwise a plain directory is restored.
+
+SERVER_NAME=$1
+LOGS=${DOMAIN_HOME}/servers/${SERVER_NAME}/logs
+
+mkdir -p ${DOMAIN_HOME}/servers/${SERVER_NAME}
+if [ -n "${LOG_EXPORT_DIR}" ]; then
+    mkdir -p ${LOG_EXPORT_DIR}/${SERVER_NAME}
+    # Keep the logs written before the export was enabled
+    if [ -d ${LOGS} ] && [ ! -L ${LOGS} ]; then
+        mv ${LOGS} ${LOGS}.`date +%Y%m%d%H%M%S`
+    fi
+    ln -sfn ${LOG_EXPORT_DIR}/${SERVER_NAME} ${LOGS}
+elif [ -L ${LOGS} ]; then
+    rm -f ${LOGS}
+fi
+
+mkdir -p ${LOGS}/
+touch ${LOGS}/${SERVER_NAME}.log
@@ -15,7 +15,78 @@ def addNodeManager(machineName):
     return machine;
 
 
-def addManagedServer(serverName, serverPort):
+def cdSSL(serverName):
+    cd('/Servers/' + serverName)
+    try:
+        cd('SSL/' + serverName)
+    except:
+        create(serverName, 'SSL')
+        cd('SSL/' + serverName)
+    return;
+
+
+def enableSSL(serverName, sslPort):
+    cdSSL(serverName)
+    set('Enabled', 'True')
+    set('ListenPort', sslPort)
+
+    cd('/')
+    return;
+
+
+# The credentials of the domain administrator are read from the Secret
+# mounted into the pod, to keep them out of the process list
+def readAdminCredential(key):
+    f = open('/u01/oracle/admin-credentials/' + key)
+    value = f.read().strip()
+    f.close()
+    return value
+
+# Every pod builds the keystores of its server at the same path
+def configureKeystores(serverName, keystoreDir, keystorePassword):
+    cd('/Servers/' + serverName)
+    set('KeyStores', 'CustomIdentityAndCustomTrust')
+    set('CustomIdentityKeyStoreFileName', '%s/identity.jks' % keystoreDir)
+    set('CustomIdentityKeyStoreType', 'JKS')
+    set('CustomIdentityKeyStorePassPhraseEncrypted', keystorePassword)
+    set('CustomTrustKeyStoreFileName', '%s/trust.jks' % keystoreDir)
+    set('CustomTrustKeyStoreType', 'JKS')
+    set('CustomTrustKeyStorePassPhraseEncrypted', keystorePassword)
+
+    cdSSL(serverName)
+    set('ServerPrivateKeyAlias', 'identity')
+    set('ServerPrivateKeyPassPhraseEncrypted', keystorePassword)
+
+    cd('/')
+    return;
+
+
+def addT3Channel(serverName, channelName, port, publicAddress, publicPort):
+    cd('/Servers/' + serverName)
+    create(channelName, 'NetworkAccessPoint')
+    cd('NetworkAccessPoints/' + channelName)
+    set('Protocol', 't3')
+    set('ListenPort', port)
+    set('PublicPort', publicPort)
+    if publicAddress:
+        set('PublicAddress', publicAddress)
+
+    cd('/')
+    return;
+
+
+def addClusterChannel(serverName, channelName, protocol, port):
+    cd('/Servers/' + serverName)
+    create(channelName, 'NetworkAccessPoint')
+    cd('NetworkAccessPoints/' + channelName)
+    set('Protocol', protocol)
+    set('ListenPort', port)
+
+    cd('/')
+    return;
+
+
+def addManagedServer(serverName, serverPort, sslPort):
     mac=addNodeManager('Machine-'+serverName)
 
     cd('/')
@@ -26,6 +97,8 @@ def addManagedServer(serverName, serverPort):
     set('Machine', mac)
 
     cd('/')
+    if sslPort > 0:
+        enableSSL(serverName, sslPort)
     return;
 
 
@@ -40,16 +113,34 @@ try:
     domainHome = sys.argv[4]
     managedServerCount = int(sys.argv[5])
     adminPort = int(sys.argv[6])
-    username = sys.argv[7]
-    password = sys.argv[8]
+    username = readAdminCredential('username')
+    password = readAdminCredential('password')
+    managedServerBasePort = int(sys.argv[7])
+    managedServerPortStep = int(sys.argv[8])
+    adminSSLPort = int(sys.argv[9])
+    managedServerSSLBasePort = int(sys.argv[10])
+    t3Channels = sys.argv[11]
+    tlsEnabled = sys.argv[12] == 'true'
+    keystoreDir = sys.argv[13]
+    # Not passed as an argument to keep it out of the process list
+    keystorePassword = os.environ.get('KEYSTORE_PASSWORD', 'changeit')
+    clusterPort = int(sys.argv[14])
+    clusterSSLPort = int(sys.argv[15])
 
     print('ORACLE_HOME              : [%s]' % oracleHome);
     print('DOMAIN_NAME              : [%s]' % domainName);
     print('DOMAIN_HOME              : [%s]' % domainHome);
     print('MANAGED_SERVER_COUNT     : [%s]' % managedServerCount);
     print('ADMIN_PORT               : [%s]' % adminPort);
+    print('ADMIN_SSL_PORT           : [%s]' % adminSSLPort);
+    print('MANAGED_SERVER_BASE_PORT : [%s]' % managedServerBasePort);
+    print('MANAGED_SERVER_PORT_STEP : [%s]' % managedServerPortStep);
+    print('MANAGED_SERVER_SSL_BASE  : [%s]' % managedServerSSLBasePort);
+    print('T3_CHANNELS              : [%s]' % t3Channels);
+    print('CLUSTER_PORT             : [%s]' % clusterPort);
+    print('CLUSTER_SSL_PORT         : [%s]' % clusterSSLPort);
+    print('TLS_ENABLED              : [%s]' % tlsEnabled);
     print('USERNAME                 : [%s]' % username);
-    print('PASSWORD                 : [%s]' % password);
 
     # Open default domain template
     # ======================
@@ -65,6 +156,17 @@ try:
     set('ListenAddress', '')
     set('ListenPort', adminPort)
 
+    if adminSSLPort > 0:
+        enableSSL('AdminServer', adminSSLPort)
+    if tlsEnabled:
+        configureKeystores('AdminServer', keystoreDir, keystorePassword)
+
+    # Channels are passed as name:port:publicAddress:publicPort,...
+    for channel in t3Channels.split(','):
+        if channel:
+            channelName, channelPort, publicAddress, publicPort = channel.split(':')
+            addT3Channel('AdminServer', channelName, int(channelPort), publicAddress, int(publicPort))
+
     # Define the user password for weblogic
     # =====================================
     cd('/')
@@ -87,18 +189,27 @@ try:
 
     # Create Managed Servers
     # =====================================
-    port = adminPort;
     serverlist = [];
     dictServer = {"serverName": "AdminServer", "port": adminPort, "host": "localhost", "podName": myPodName}
     serverlist.append(dictServer)
     for x in range(1, managedServerCount + 1):
-        port += 2
+        port = managedServerBasePort + (x - 1) * managedServerPortStep
+        sslPort = 0
+        if managedServerSSLBasePort > 0:
+            sslPort = managedServerSSLBasePort + (x - 1) * managedServerPortStep
         servername = 'managedserver-' + str((x - 1))
         host = 'localhost'
         dictServer = {"serverName": servername, "port": port, "host": host, "podName": ""}
         serverlist.append(dictServer)
 
-        addManagedServer(servername, port)
+        addManagedServer(servername, port, sslPort)
+        # Lets the cluster Service reach every managed server on the same ports
+        if clusterPort > 0:
+            addClusterChannel(servername, 'cluster-http', 'http', clusterPort)
+        if clusterSSLPort > 0:
+            addClusterChannel(servername, 'cluster-https', 'https', clusterSSLPort)
+        if tlsEnabled:
+            configureKeystores(servername, keystoreDir, keystorePassword)
 
     # Write Domain
     # ============
@@ -0,0 +1,35 @@
+#!/bin/bash
+# Fails once the WebLogic server hosted by the pod has died or reports FAILED,
+# or once the Node Manager running the server has died.
+# Passes while the pod is still preparing, before a server has been started.
+
+if [ ! -f /tmp/weblogic-server.env ]; then
+    exit 0
+fi
+. /tmp/weblogic-server.env
+
+# Under a Node Manager the pod lives as long as the Node Manager, which
+# restarts the server itself
+if [ "${NODE_MANAGER_ENABLED}" = "true" ] && [ "${SERVER_NAME}" != "AdminServer" ]; then
+    if ! pgrep -f "weblogic.NodeManager" > /dev/null; then
+        echo "Node Manager of ${SERVER_NAME} is not running"
+        exit 1
+    fi
+    exit 0
+fi
+
+if ! pgrep -f "weblogic.Name=${SERVER_NAME}" > /dev/null; then
+    echo "WebLogic server ${SERVER_NAME} is not running"
+    exit 1
+fi
+
+. /u01/oracle/scripts/adminCredentials.sh
+runtime=$(curl -s -m 8 -K <(adminCurlConfig) -H "Accept: application/json" \
+    "http://localhost:${SERVER_PORT}/management/weblogic/latest/serverRuntime?links=none&fields=state")
+
+if echo "$runtime" | grep -q '"state": *"FAILED"'; then
+    echo "WebLogic server ${SERVER_NAME} is FAILED"
+    exit 1
+fi
+
+exit 0
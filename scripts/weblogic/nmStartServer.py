@@ -0,0 +1,43 @@
+import os
+import sys
+
+from java.util import Properties
+
+# The credentials of the domain administrator are read from the Secret
+# mounted into the pod, to keep them out of the process list
+def readAdminCredential(key):
+    f = open('/u01/oracle/admin-credentials/' + key)
+    value = f.read().strip()
+    f.close()
+    return value
+
+# Starts a managed server through the Node Manager of its pod. The server JVM
+# gets the memory arguments and Java options set on the container, and is
+# restarted by the Node Manager when it fails.
+
+try:
+    nodeManagerPort = int(sys.argv[1])
+    domainName = sys.argv[2]
+    domainHome = sys.argv[3]
+    serverName = sys.argv[4]
+    adminURL = sys.argv[5]
+    username = readAdminCredential('username')
+    password = readAdminCredential('password')
+
+    props = Properties()
+    props.put('AdminURL', adminURL)
+    props.put('Arguments', '%s %s' % (os.environ.get('USER_MEM_ARGS', ''), os.environ.get('JAVA_OPTIONS', '')))
+    props.put('AutoRestart', 'true')
+
+    nmConnect(username, password, 'localhost', nodeManagerPort, domainName, domainHome, 'plain')
+    nmStart(serverName, domainHome, props)
+    nmDisconnect()
+
+    # Exit WLST
+    # =========
+    exit()
+
+except Exception, e:
+    e.printStackTrace()
+    dumpStack()
+    raise ("Start Server Failed")
@@ -0,0 +1,22 @@
+#!/bin/bash
+# Reports the pod ready once the WebLogic server it hosts is RUNNING and
+# healthy, as seen through the server's REST management API.
+
+if [ ! -f /tmp/weblogic-server.env ]; then
+    exit 1
+fi
+. /tmp/weblogic-server.env
+. /u01/oracle/scripts/adminCredentials.sh
+
+runtime=$(curl -s -m 4 -K <(adminCurlConfig) -H "Accept: application/json" \
+    "http://localhost:${SERVER_PORT}/management/weblogic/latest/serverRuntime?links=none&fields=state,healthState")
+
+if ! echo "$runtime" | grep -q '"state": *"RUNNING"'; then
+    exit 1
+fi
+
+if echo "$runtime" | grep -q '"state": *"\(failed\|critical\)"'; then
+    exit 1
+fi
+
+exit 0
@@ -0,0 +1,49 @@
+#!/bin/bash
+# Shuts the WebLogic server named by the first argument down through the REST
+# management API of the admin server, as configured on the pod through
+# SHUTDOWN_TYPE, SHUTDOWN_TIMEOUT_SECONDS and SHUTDOWN_IGNORE_SESSIONS.
+
+SERVER_NAME=$1
+SHUTDOWN_TYPE=${SHUTDOWN_TYPE:-Graceful}
+SHUTDOWN_TIMEOUT_SECONDS=${SHUTDOWN_TIMEOUT_SECONDS:-30}
+SHUTDOWN_IGNORE_SESSIONS=${SHUTDOWN_IGNORE_SESSIONS:-false}
+
+. /u01/oracle/scripts/adminCredentials.sh
+
+# A terminating admin server pod is no longer reachable through its Service
+ADMIN_HOST=${DOMAIN_NAME}
+if [ "${SERVER_NAME}" = "AdminServer" ]; then
+    ADMIN_HOST=localhost
+fi
+
+ADMIN_REST_URL="http://${ADMIN_HOST}:${ADMIN_PORT}"
+CURL_OPTIONS=""
+if [ "${TLS_ENABLED}" = "true" ]; then
+    ADMIN_REST_URL="https://${ADMIN_HOST}:${ADMIN_SSL_PORT}"
+    CURL_OPTIONS="--cacert /u01/oracle/tls/ca.crt"
+fi
+
+if [ "${SHUTDOWN_TYPE}" = "Forced" ]; then
+    action=forceShutdown
+    body='{}'
+else
+    action=shutdown
+    body="{\"timeout\": ${SHUTDOWN_TIMEOUT_SECONDS}, \"ignoreSessions\": ${SHUTDOWN_IGNORE_SESSIONS}, \"waitForAllSessions\": false}"
+fi
+
+echo "Shutting down ${SERVER_NAME}: ${action}, timeout ${SHUTDOWN_TIMEOUT_SECONDS}s, ignore sessions ${SHUTDOWN_IGNORE_SESSIONS}"
+status=$(curl -s -o /dev/null -w "%{http_code}" -m $((SHUTDOWN_TIMEOUT_SECONDS + 20)) ${CURL_OPTIONS} \
+    -K <(adminCurlConfig) -H "X-Requested-By: weblogic-operator" \
+    -H "Accept: application/json" -H "Content-Type: application/json" -X POST -d "${body}" \
+    "${ADMIN_REST_URL}/management/weblogic/latest/domainRuntime/serverLifeCycleRuntimes/${SERVER_NAME}/${action}")
+
+if [[ "$status" == 2* ]]; then
+    echo "${SERVER_NAME} is shut down"
+    exit 0
+fi
+
+echo "REST shutdown of ${SERVER_NAME} failed with status ${status}"
+if [ "${SERVER_NAME}" = "AdminServer" ]; then
+    ${DOMAIN_HOME}/bin/stopWebLogic.sh
+fi
+exit 1
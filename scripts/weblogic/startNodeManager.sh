@@ -0,0 +1,49 @@
+#!/bin/bash
+# Runs a Node Manager for the managed server named by the first argument and
+# starts the server through it, connecting to the admin server at the URL
+# given as second argument. Returns once the Node Manager stops, so the pod
+# lives on while WebLogic restarts a failed server.
+
+SERVER_NAME=$1
+ADMIN_URL=$2
+NODE_MANAGER_PORT=${NODE_MANAGER_PORT:-5556}
+
+# Every pod mounts the same domain home, so each server gets its own Node Manager home
+export NODEMGR_HOME=${DOMAIN_HOME}/nodemanager/${SERVER_NAME}
+mkdir -p ${NODEMGR_HOME}
+
+cat > ${NODEMGR_HOME}/nodemanager.properties <<PROPERTIES
+NodeManagerHome=${NODEMGR_HOME}
+DomainsFile=${NODEMGR_HOME}/nodemanager.domains
+LogFile=${NODEMGR_HOME}/nodemanager.log
+ListenPort=${NODE_MANAGER_PORT}
+SecureListener=false
+CrashRecoveryEnabled=true
+StartScriptEnabled=false
+QuitEnabled=false
+PropertiesVersion=12.2.1
+PROPERTIES
+echo "${DOMAIN_NAME}=${DOMAIN_HOME}" > ${NODEMGR_HOME}/nodemanager.domains
+
+echo "Starting Node Manager for ${SERVER_NAME} on port ${NODE_MANAGER_PORT}..."
+${ORACLE_HOME}/wlserver/server/bin/startNodeManager.sh &
+nodemanager=$!
+
+until (echo > /dev/tcp/localhost/${NODE_MANAGER_PORT}) 2> /dev/null; do
+    if ! kill -0 ${nodemanager} 2> /dev/null; then
+        echo "Node Manager for ${SERVER_NAME} exited"
+        exit 1
+    fi
+    sleep 2
+done
+
+echo "Starting ${SERVER_NAME} through the Node Manager..."
+$ORACLE_HOME/oracle_common/common/bin/wlst.sh -skipWLSModuleScanning /u01/oracle/scripts/nmStartServer.py \
+                                                    "${NODE_MANAGER_PORT}" $DOMAIN_NAME $DOMAIN_HOME ${SERVER_NAME} "${ADMIN_URL}"
+
+# The log exporter sidecar prints the logs when they are exported
+if [ -z "${LOG_EXPORT_DIR}" ]; then
+    tail -f ${DOMAIN_HOME}/servers/${SERVER_NAME}/logs/${SERVER_NAME}.log &
+fi
+
+wait ${nodemanager}
@@ -24,8 +24,12 @@ if [ -d ${DOMAIN_HOME} ]; then
     mskey="serverName"
     msre="\"($mskey)\": \"([^\"]*)\""
 
+    portkey="port"
+    portre="\"($portkey)\": ([0-9]+)"
+
     mypodname=${MY_POD_NAME}
     msname=""
+    msport=""
     foundms=false
 
     while IFS='' read -r line || [[ -n "$line" ]]; do
@@ -40,6 +44,9 @@ if [ -d ${DOMAIN_HOME} ]; then
                         if [[ $msname != "AdminServer" ]]; then
                             echo "Found available Managed Server $msname"
                             foundms=true
+                            if [[ $line =~ $portre ]]; then
+                                msport="${BASH_REMATCH[2]}"
+                            fi
                             line=${line/\"podName\": \"\"/\"podName\": \"$mypodname\"}
                         else
                             msname=""
@@ -63,11 +70,46 @@ if [ -d ${DOMAIN_HOME} ]; then
         mkdir -p ${DOMAIN_HOME}/servers/${msname}/security/
         cp -r ${DOMAIN_HOME}/servers/AdminServer/security/boot.properties ${DOMAIN_HOME}/servers/${msname}/security/boot.properties
 
-        ${DOMAIN_HOME}/bin/startManagedWebLogic.sh ${msname} "t3://${DOMAIN_NAME}:7001"
+        if [ -d /u01/oracle/config-overrides ]; then
+            mkdir -p ${DOMAIN_HOME}/optconfig
+            for override in /u01/oracle/config-overrides/*.xml; do
+                [ -f "$override" ] && cp "$override" ${DOMAIN_HOME}/optconfig/
+            done
+        fi
+
+        # Tells the readiness and liveness probes which server to check
+        echo "SERVER_NAME=${msname}" > /tmp/weblogic-server.env
+        echo "SERVER_PORT=${msport}" >> /tmp/weblogic-server.env
+
+        # Trust the domain CA when connecting to the admin server over t3s. A
+        # JKS truststore is read without its password.
+        ADMIN_URL="t3://${DOMAIN_NAME}:${ADMIN_PORT}"
+        if [ "${TLS_ENABLED}" = "true" ]; then
+            /u01/oracle/scripts/createKeystores.sh
+            ADMIN_URL="t3s://${DOMAIN_NAME}:${ADMIN_SSL_PORT}"
+            TRUST_OPTIONS="-Dweblogic.security.TrustKeyStore=CustomTrust -Dweblogic.security.CustomTrustKeyStoreType=JKS"
+            TRUST_OPTIONS="${TRUST_OPTIONS} -Dweblogic.security.CustomTrustKeyStoreFileName=/u01/oracle/keystores/trust.jks"
+        fi
+
+        # USER_MEM_ARGS and JAVA_OPTIONS are set on the container by the operator
+        echo "USER_MEM_ARGS=${USER_MEM_ARGS}"
+        echo "JAVA_OPTIONS=${JAVA_OPTIONS}"
+        JAVA_OPTIONS="${JAVA_OPTIONS} ${TRUST_OPTIONS}"
+        export USER_MEM_ARGS JAVA_OPTIONS
 
-        mkdir -p ${DOMAIN_HOME}/servers/${msname}/logs/
-        touch ${DOMAIN_HOME}/servers/${msname}/logs/${msname}.log
-        tail -f ${DOMAIN_HOME}/servers/${msname}/logs/${msname}.log &
+        /u01/oracle/scripts/exportLogs.sh ${msname}
+
+        if [ "${NODE_MANAGER_ENABLED}" = "true" ]; then
+            # Runs as long as the Node Manager, which restarts the server when it fails
+            /u01/oracle/scripts/startNodeManager.sh ${msname} "${ADMIN_URL}"
+        else
+            ${DOMAIN_HOME}/bin/startManagedWebLogic.sh ${msname} "${ADMIN_URL}"
+
+            # The log exporter sidecar prints the logs when they are exported
+            if [ -z "${LOG_EXPORT_DIR}" ]; then
+                tail -f ${DOMAIN_HOME}/servers/${msname}/logs/${msname}.log &
+            fi
+        fi
     fi
 fi
 
@@ -60,7 +60,19 @@ if [ -d ${DOMAIN_HOME} ]; then
     if [[ ! -z "${msname// }" ]]; then
         echo "Stopping $msname..."
 
-        ${DOMAIN_HOME}/bin/stopManagedWebLogic.sh ${msname} "t3://${DOMAIN_NAME}:7001"
+        # Trust the domain CA when connecting to the admin server over t3s
+        ADMIN_URL="t3://${DOMAIN_NAME}:${ADMIN_PORT}"
+        if [ "${TLS_ENABLED}" = "true" ]; then
+            ADMIN_URL="t3s://${DOMAIN_NAME}:${ADMIN_SSL_PORT}"
+            TRUST_OPTIONS="-Dweblogic.security.TrustKeyStore=CustomTrust -Dweblogic.security.CustomTrustKeyStoreType=JKS"
+            TRUST_OPTIONS="${TRUST_OPTIONS} -Dweblogic.security.CustomTrustKeyStoreFileName=/u01/oracle/keystores/trust.jks"
+            export WLST_PROPERTIES="${WLST_PROPERTIES} ${TRUST_OPTIONS}"
+        fi
+
+        # Shut down as configured on the pod, the stop script does not drain sessions
+        if ! /u01/oracle/scripts/shutdownServer.sh ${msname}; then
+            ${DOMAIN_HOME}/bin/stopManagedWebLogic.sh ${msname} "${ADMIN_URL}"
+        fi
     fi
 fi
 